@@ -139,7 +139,55 @@ func CoerceArg(v interface{}) (interface{}, error) {
 	}
 }
 
+// PositionalArgs builds an ArgList from values in order, coercing each as
+// CoerceArg does. Values CoerceArg does not support are kept as they are
+// and left to the server to validate.
+func PositionalArgs(values ...interface{}) ArgList {
+	out := make(ArgList, len(values))
+	for i, v := range values {
+		if coerced, err := CoerceArg(v); err == nil {
+			v = coerced
+		}
+		out[i] = v
+	}
+	return out
+}
+
 // NormalizeArgKey lowercases an argument key for case-insensitive matching.
 func NormalizeArgKey(key string) string {
 	return strings.ToLower(key)
 }
+
+// MergeArgs combines several arg maps into a new one, e.g. defaults, then
+// user input, then env-derived values. Later maps win on conflicting keys,
+// except that when both values are nested maps (map[string]interface{})
+// they are merged recursively under the same rule. Keys are compared
+// exactly, not through NormalizeArgKey. Nil maps are skipped.
+//
+// The inputs are never modified: nested maps are copied into the result, so
+// it can be changed freely afterwards. Other values, including slices, are
+// shared with the inputs.
+func MergeArgs(maps ...map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, m := range maps {
+		mergeArgsInto(out, m)
+	}
+	return out
+}
+
+// mergeArgsInto merges src into dst, which must be owned by the caller.
+func mergeArgsInto(dst, src map[string]interface{}) {
+	for k, v := range src {
+		nested, ok := v.(map[string]interface{})
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		target, ok := dst[k].(map[string]interface{})
+		if !ok {
+			target = make(map[string]interface{}, len(nested))
+			dst[k] = target
+		}
+		mergeArgsInto(target, nested)
+	}
+}
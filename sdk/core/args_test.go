@@ -129,3 +129,50 @@ func TestNormalizeArgKey(t *testing.T) {
 		t.Error("expected lowercase")
 	}
 }
+
+func TestMergeArgs(t *testing.T) {
+	defaults := map[string]interface{}{
+		"quantity": int64(1),
+		"policy":   map[string]interface{}{"name": "default", "ttl": int64(60)},
+	}
+	user := map[string]interface{}{
+		"quantity": int64(5),
+		"policy":   map[string]interface{}{"name": "custom"},
+		"sender":   "addr1",
+	}
+	got := core.MergeArgs(defaults, nil, user)
+
+	if got["quantity"] != int64(5) || got["sender"] != "addr1" {
+		t.Errorf("expected later maps to win, got %#v", got)
+	}
+	policy := got["policy"].(map[string]interface{})
+	if policy["name"] != "custom" || policy["ttl"] != int64(60) {
+		t.Errorf("expected nested maps to be deep-merged, got %#v", policy)
+	}
+
+	policy["ttl"] = int64(0)
+	if defaults["policy"].(map[string]interface{})["ttl"] != int64(60) {
+		t.Error("MergeArgs result must not share nested maps with its inputs")
+	}
+	if defaults["quantity"] != int64(1) || len(user["policy"].(map[string]interface{})) != 1 {
+		t.Error("MergeArgs must not modify its inputs")
+	}
+}
+
+func TestMergeArgsScalarReplacesMap(t *testing.T) {
+	got := core.MergeArgs(
+		map[string]interface{}{"datum": map[string]interface{}{"a": int64(1)}},
+		map[string]interface{}{"datum": "0x00"},
+	)
+	if got["datum"] != "0x00" {
+		t.Errorf("expected a later scalar to replace a map, got %#v", got["datum"])
+	}
+}
+
+func TestPositionalArgs(t *testing.T) {
+	type custom struct{ N int }
+	args := core.PositionalArgs(7, []byte{0xde, 0xad}, core.BoolArg(true), custom{1})
+	if len(args) != 4 || args[0] != int64(7) || args[1] != "0xdead" || args[2] != true || args[3] != (custom{1}) {
+		t.Errorf("expected values coerced in order, got %#v", args)
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ArgsBuilder assembles an ArgMap with typed setters, so wrong value kinds
+// and empty keys are caught before a request reaches the server:
+//
+//	args, err := core.NewArgs().
+//	    SetAddress("recipient", addr).
+//	    SetInt("amount", 100).
+//	    Build()
+//
+// Setters record errors instead of returning them; Build reports all of
+// them at once.
+type ArgsBuilder struct {
+	args ArgMap
+	errs []error
+}
+
+// NewArgs returns an empty ArgsBuilder.
+func NewArgs() *ArgsBuilder {
+	return &ArgsBuilder{args: ArgMap{}}
+}
+
+// Set stores a native Go value, converted with CoerceArg.
+func (b *ArgsBuilder) Set(key string, v interface{}) *ArgsBuilder {
+	coerced, err := CoerceArg(v)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("arg %q: %w", key, err))
+		return b
+	}
+	return b.put(key, coerced)
+}
+
+// SetInt stores an integer argument.
+func (b *ArgsBuilder) SetInt(key string, v int64) *ArgsBuilder {
+	return b.put(key, IntArg(v).ToJSON())
+}
+
+// SetBigInt stores an arbitrary-precision integer argument.
+func (b *ArgsBuilder) SetBigInt(key string, v *big.Int) *ArgsBuilder {
+	if v == nil {
+		b.errs = append(b.errs, fmt.Errorf("arg %q: nil big.Int", key))
+		return b
+	}
+	return b.put(key, BigIntArg(v).ToJSON())
+}
+
+// SetBool stores a boolean argument.
+func (b *ArgsBuilder) SetBool(key string, v bool) *ArgsBuilder {
+	return b.put(key, BoolArg(v).ToJSON())
+}
+
+// SetString stores a string argument.
+func (b *ArgsBuilder) SetString(key string, v string) *ArgsBuilder {
+	return b.put(key, StringArg(v).ToJSON())
+}
+
+// SetBytes stores a byte-array argument, hex-encoded on the wire.
+func (b *ArgsBuilder) SetBytes(key string, v []byte) *ArgsBuilder {
+	return b.put(key, BytesArg(v).ToJSON())
+}
+
+// SetAddress stores a bech32 address argument.
+func (b *ArgsBuilder) SetAddress(key string, v Address) *ArgsBuilder {
+	if v == "" {
+		b.errs = append(b.errs, fmt.Errorf("arg %q: empty address", key))
+		return b
+	}
+	return b.put(key, AddressArg(v).ToJSON())
+}
+
+// SetUtxoRef stores a UTxO reference argument (format: "txid#index").
+func (b *ArgsBuilder) SetUtxoRef(key string, v UtxoRef) *ArgsBuilder {
+	if !strings.Contains(v, "#") {
+		b.errs = append(b.errs, fmt.Errorf("arg %q: invalid UTxO reference %q (expected txid#index)", key, v))
+		return b
+	}
+	return b.put(key, UtxoRefArg(v).ToJSON())
+}
+
+func (b *ArgsBuilder) put(key string, v interface{}) *ArgsBuilder {
+	if strings.TrimSpace(key) == "" {
+		b.errs = append(b.errs, errors.New("arg key must not be empty"))
+		return b
+	}
+	b.args[key] = v
+	return b
+}
+
+// Build returns a copy of the assembled ArgMap, or the errors recorded by
+// the setters.
+func (b *ArgsBuilder) Build() (ArgMap, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	out := make(ArgMap, len(b.args))
+	for k, v := range b.args {
+		out[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+func TestArgsBuilder(t *testing.T) {
+	large, _ := new(big.Int).SetString("99999999999999999999", 10)
+	args, err := core.NewArgs().
+		SetAddress("recipient", "addr_test1abc").
+		SetInt("amount", 100).
+		SetBigInt("big", large).
+		SetBytes("datum", []byte{0xDE, 0xAD}).
+		SetBool("flag", true).
+		SetString("memo", "hi").
+		SetUtxoRef("input", "abcd#0").
+		Set("native", 7).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"recipient": "addr_test1abc",
+		"amount":    int64(100),
+		"datum":     "0xdead",
+		"flag":      true,
+		"memo":      "hi",
+		"input":     "abcd#0",
+		"native":    int64(7),
+	}
+	for k, v := range want {
+		if args[k] != v {
+			t.Errorf("%s: expected %v (%T), got %v (%T)", k, v, v, args[k], args[k])
+		}
+	}
+	if s, ok := args["big"].(string); !ok || !strings.HasPrefix(s, "0x") {
+		t.Errorf("expected hex-encoded big int, got %v", args["big"])
+	}
+}
+
+func TestArgsBuilderCollectsErrors(t *testing.T) {
+	_, err := core.NewArgs().
+		SetInt("", 1).
+		SetUtxoRef("input", "nohash").
+		Set("bad", struct{}{}).
+		Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"must not be empty", "invalid UTxO reference", `arg "bad"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %q", want, err)
+		}
+	}
+}
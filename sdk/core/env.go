@@ -0,0 +1,132 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Env keys written by the EnvBuilder setters. TRP itself does not fix env
+// keys: each protocol's env block declares its own. Protocols using other
+// names for these values can store them with EnvBuilder.Set.
+const (
+	EnvKeyNetwork        = "network"
+	EnvKeyTip            = "tip"
+	EnvKeyProtocolParams = "protocolParams"
+)
+
+// mainnetMagic is the network magic of Cardano mainnet.
+const mainnetMagic = 764824073
+
+// Network identifies a Cardano network by the network id carried in its
+// addresses (1 for mainnet, 0 for testnets) and its network magic.
+type Network struct {
+	ID    uint8  `json:"id"`
+	Magic uint32 `json:"magic"`
+}
+
+// Well-known Cardano networks.
+var (
+	Mainnet = Network{ID: 1, Magic: mainnetMagic}
+	Preprod = Network{ID: 0, Magic: 1}
+	Preview = Network{ID: 0, Magic: 2}
+)
+
+// validate rejects network ids other than 0 and 1, and mismatches between
+// the id and the magic: only mainnet's magic goes with id 1.
+func (n Network) validate() error {
+	switch {
+	case n.ID > 1:
+		return fmt.Errorf("network id %d is neither mainnet (1) nor testnet (0)", n.ID)
+	case n.ID == 1 && n.Magic != mainnetMagic:
+		return fmt.Errorf("mainnet network id with testnet magic %d", n.Magic)
+	case n.ID == 0 && n.Magic == mainnetMagic:
+		return errors.New("testnet network id with mainnet magic")
+	}
+	return nil
+}
+
+// Tip is a point on the chain: a slot and the hash of the block there.
+type Tip struct {
+	Slot uint64 `json:"slot"`
+	Hash string `json:"hash"` // Hex-encoded 32-byte block hash
+}
+
+// EnvBuilder assembles an EnvMap with typed setters for common env values,
+// so malformed ones are caught before a request reaches the server:
+//
+//	env, err := core.NewEnv().
+//	    SetNetwork(core.Preprod).
+//	    SetTip(core.Tip{Slot: slot, Hash: blockHash}).
+//	    Build()
+//
+// Like ArgsBuilder, setters record errors and Build reports all of them.
+type EnvBuilder struct {
+	env  EnvMap
+	errs []error
+}
+
+// NewEnv returns an empty EnvBuilder.
+func NewEnv() *EnvBuilder {
+	return &EnvBuilder{env: EnvMap{}}
+}
+
+// Set stores an arbitrary env value under key.
+func (b *EnvBuilder) Set(key string, v interface{}) *EnvBuilder {
+	return b.put(key, v)
+}
+
+// SetNetwork stores the network under EnvKeyNetwork, rejecting an id that
+// does not match the magic.
+func (b *EnvBuilder) SetNetwork(n Network) *EnvBuilder {
+	if err := n.validate(); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("env %q: %w", EnvKeyNetwork, err))
+		return b
+	}
+	return b.put(EnvKeyNetwork, n)
+}
+
+// SetTip stores the chain tip under EnvKeyTip.
+func (b *EnvBuilder) SetTip(t Tip) *EnvBuilder {
+	if h, err := hex.DecodeString(t.Hash); err != nil || len(h) != 32 {
+		b.errs = append(b.errs, fmt.Errorf("env %q: block hash %q is not 32 hex-encoded bytes", EnvKeyTip, t.Hash))
+		return b
+	}
+	return b.put(EnvKeyTip, t)
+}
+
+// SetProtocolParams stores protocol parameters under EnvKeyProtocolParams.
+// params may be any value that encodes as a JSON object, e.g. the
+// parameters as returned by a chain indexer.
+func (b *EnvBuilder) SetProtocolParams(params interface{}) *EnvBuilder {
+	raw, err := json.Marshal(params)
+	if err != nil || !strings.HasPrefix(string(raw), "{") {
+		b.errs = append(b.errs, fmt.Errorf("env %q: protocol parameters must encode as a JSON object", EnvKeyProtocolParams))
+		return b
+	}
+	return b.put(EnvKeyProtocolParams, json.RawMessage(raw))
+}
+
+func (b *EnvBuilder) put(key string, v interface{}) *EnvBuilder {
+	if strings.TrimSpace(key) == "" {
+		b.errs = append(b.errs, errors.New("env key must not be empty"))
+		return b
+	}
+	b.env[key] = v
+	return b
+}
+
+// Build returns a copy of the assembled EnvMap, or the errors recorded by
+// the setters.
+func (b *EnvBuilder) Build() (EnvMap, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	out := make(EnvMap, len(b.env))
+	for k, v := range b.env {
+		out[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package core_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+func TestEnvBuilder(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	env, err := core.NewEnv().
+		SetNetwork(core.Preprod).
+		SetTip(core.Tip{Slot: 42, Hash: hash}).
+		SetProtocolParams(map[string]interface{}{"minFeeA": 44}).
+		Set("custom", "x").
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	got, _ := json.Marshal(env)
+	want := `{"custom":"x","network":{"id":0,"magic":1},"protocolParams":{"minFeeA":44},"tip":{"slot":42,"hash":"` + hash + `"}}`
+	if string(got) != want {
+		t.Errorf("unexpected env:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestEnvBuilderCollectsErrors(t *testing.T) {
+	_, err := core.NewEnv().
+		SetNetwork(core.Network{ID: 1, Magic: 2}).
+		SetTip(core.Tip{Slot: 1, Hash: "abc"}).
+		SetProtocolParams([]int{1}).
+		Set(" ", 1).
+		Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"mainnet network id with testnet magic", "block hash", "JSON object", "must not be empty"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %q", want, err)
+		}
+	}
+
+	if _, err := core.NewEnv().SetNetwork(core.Mainnet).Build(); err != nil {
+		t.Errorf("expected mainnet to be accepted, got %v", err)
+	}
+}
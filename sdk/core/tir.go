@@ -0,0 +1,147 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Supported TirEnvelope content encodings.
+const (
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+)
+
+// NewTirEnvelope encodes raw TIR bytecode under the given encoding and wraps
+// it in a TirEnvelope.
+func NewTirEnvelope(version string, bytecode []byte, encoding string) (TirEnvelope, error) {
+	var content string
+	switch encoding {
+	case EncodingHex:
+		content = hex.EncodeToString(bytecode)
+	case EncodingBase64:
+		content = base64.StdEncoding.EncodeToString(bytecode)
+	default:
+		return TirEnvelope{}, unsupportedEncoding(encoding)
+	}
+	return TirEnvelope{Content: content, Encoding: encoding, Version: version}, nil
+}
+
+// DecodeContent returns the raw TIR bytecode, decoding Content according to
+// the declared Encoding. With Lenient set, content labelled base64 that does
+// not decode as such is retried as hex.
+func (t TirEnvelope) DecodeContent() ([]byte, error) {
+	switch t.Encoding {
+	case EncodingHex:
+		b, err := hex.DecodeString(t.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytecode: not valid hex: %w", err)
+		}
+		return b, nil
+	case EncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(t.Content)
+		if err == nil {
+			return b, nil
+		}
+		if !t.Lenient {
+			return nil, fmt.Errorf("invalid bytecode: not valid base64: %w", err)
+		}
+		b, hexErr := hex.DecodeString(t.Content)
+		if hexErr != nil {
+			return nil, fmt.Errorf("invalid bytecode: not valid base64 (%v) nor, leniently, hex (%v)", err, hexErr)
+		}
+		return b, nil
+	default:
+		return nil, unsupportedEncoding(t.Encoding)
+	}
+}
+
+// Canonical returns t labelled with the encoding its content really uses: a
+// Lenient envelope whose base64 content only decodes through the hex
+// fallback comes back labelled hex, so a strict server can decode it too.
+// Other envelopes are returned unchanged.
+func (t TirEnvelope) Canonical() TirEnvelope {
+	if !t.Lenient || t.Encoding != EncodingBase64 {
+		return t
+	}
+	if _, err := base64.StdEncoding.DecodeString(t.Content); err == nil {
+		return t
+	}
+	if _, err := hex.DecodeString(t.Content); err == nil {
+		t.Encoding = EncodingHex
+	}
+	return t
+}
+
+// ComputeHash returns the hex-encoded blake2b-256 digest of the decoded
+// bytecode, the value to send as Hash.
+func (t TirEnvelope) ComputeHash() (string, error) {
+	bytecode, err := t.DecodeContent()
+	if err != nil {
+		return "", err
+	}
+	sum := blake2b.Sum256(bytecode)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Validate checks that the envelope declares a supported encoding and that
+// its content actually decodes under that encoding.
+func (t TirEnvelope) Validate() error {
+	if t.Content == "" {
+		return fmt.Errorf("invalid bytecode: empty content")
+	}
+	_, err := t.DecodeContent()
+	return err
+}
+
+// LoadTirEnvelope reads a JSON-encoded TIR artifact (as emitted by the tx3
+// toolchain) from path. See ParseTirEnvelope for the validation applied.
+func LoadTirEnvelope(path string) (TirEnvelope, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TirEnvelope{}, fmt.Errorf("failed to open TIR artifact: %w", err)
+	}
+	defer f.Close()
+	tir, err := ParseTirEnvelope(f)
+	if err != nil {
+		return TirEnvelope{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return tir, nil
+}
+
+// ParseTirEnvelope decodes a JSON-encoded TIR artifact from r. The version,
+// encoding and content fields are required, and the content must be
+// non-empty bytecode under the declared encoding.
+func ParseTirEnvelope(r io.Reader) (TirEnvelope, error) {
+	var tir TirEnvelope
+	if err := json.NewDecoder(r).Decode(&tir); err != nil {
+		return TirEnvelope{}, fmt.Errorf("invalid TIR artifact: %w", err)
+	}
+	var missing []string
+	for _, f := range []struct{ name, value string }{
+		{"version", tir.Version},
+		{"encoding", tir.Encoding},
+		{"content", tir.Content},
+	} {
+		if f.value == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return TirEnvelope{}, fmt.Errorf("invalid TIR artifact: missing %s", strings.Join(missing, ", "))
+	}
+	if err := tir.Validate(); err != nil {
+		return TirEnvelope{}, fmt.Errorf("invalid TIR artifact: %w", err)
+	}
+	return tir, nil
+}
+
+func unsupportedEncoding(encoding string) error {
+	return fmt.Errorf("unsupported TIR encoding %q (supported: %s, %s)", encoding, EncodingHex, EncodingBase64)
+}
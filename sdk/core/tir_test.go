@@ -0,0 +1,143 @@
+package core_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+func TestTirEnvelopeValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		tir     core.TirEnvelope
+		wantErr string
+	}{
+		{"hex", core.TirEnvelope{Content: "aabbcc", Encoding: "hex"}, ""},
+		{"base64", core.TirEnvelope{Content: "qrvM", Encoding: "base64"}, ""},
+		{"hex labelled base64", core.TirEnvelope{Content: "aabbcc!", Encoding: "base64"}, "not valid base64"},
+		{"lenient hex labelled base64", core.TirEnvelope{Content: "aabbcc", Encoding: "base64", Lenient: true}, ""},
+		{"lenient garbage", core.TirEnvelope{Content: "aabbccd", Encoding: "base64", Lenient: true}, "nor, leniently, hex"},
+		{"bad hex", core.TirEnvelope{Content: "xyz", Encoding: "hex"}, "not valid hex"},
+		{"unknown encoding", core.TirEnvelope{Content: "aabb", Encoding: "utf8"}, "supported: hex, base64"},
+		{"empty", core.TirEnvelope{Encoding: "hex"}, "empty content"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.tir.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTirEnvelopeRoundTrip(t *testing.T) {
+	bytecode := []byte{0xde, 0xad, 0xbe, 0xef}
+	for _, encoding := range []string{core.EncodingHex, core.EncodingBase64} {
+		tir, err := core.NewTirEnvelope("v1beta0", bytecode, encoding)
+		if err != nil {
+			t.Fatalf("NewTirEnvelope(%s) failed: %v", encoding, err)
+		}
+		if tir.Encoding != encoding || tir.Version != "v1beta0" {
+			t.Errorf("unexpected envelope metadata: %+v", tir)
+		}
+		decoded, err := tir.DecodeContent()
+		if err != nil {
+			t.Fatalf("DecodeContent(%s) failed: %v", encoding, err)
+		}
+		if string(decoded) != string(bytecode) {
+			t.Errorf("round trip mismatch for %s: got %x", encoding, decoded)
+		}
+	}
+
+	if _, err := core.NewTirEnvelope("v1beta0", bytecode, "utf8"); err == nil {
+		t.Error("expected error for unsupported encoding")
+	}
+}
+
+func TestLoadTirEnvelope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transfer.tir")
+	artifact := `{"version":"v1beta0","encoding":"hex","content":"aabbcc"}`
+	if err := os.WriteFile(path, []byte(artifact), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tir, err := core.LoadTirEnvelope(path)
+	if err != nil {
+		t.Fatalf("LoadTirEnvelope failed: %v", err)
+	}
+	if tir.Content != "aabbcc" || tir.Encoding != "hex" || tir.Version != "v1beta0" {
+		t.Errorf("unexpected envelope %+v", tir)
+	}
+
+	_, err = core.LoadTirEnvelope(filepath.Join(t.TempDir(), "missing.tir"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestParseTirEnvelopeRejectsIncompleteArtifacts(t *testing.T) {
+	cases := map[string]struct {
+		artifact string
+		wantErr  string
+	}{
+		"malformed":     {`{"version":`, "invalid TIR artifact"},
+		"missing":       {`{"content":"aa"}`, "missing version, encoding"},
+		"empty content": {`{"version":"v1beta0","encoding":"hex","content":""}`, "missing content"},
+		"bad bytecode":  {`{"version":"v1beta0","encoding":"hex","content":"zz"}`, "not valid hex"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := core.ParseTirEnvelope(strings.NewReader(tc.artifact))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTirEnvelopeComputeHash(t *testing.T) {
+	hexHash, err := core.TirEnvelope{Content: "aabbcc", Encoding: "hex"}.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	b64Hash, err := core.TirEnvelope{Content: "qrvM", Encoding: "base64"}.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash failed: %v", err)
+	}
+	if len(hexHash) != 64 || hexHash != b64Hash {
+		t.Errorf("expected the same 32-byte digest for both encodings, got %q and %q", hexHash, b64Hash)
+	}
+	if _, err := (core.TirEnvelope{Content: "zz", Encoding: "hex"}).ComputeHash(); err == nil {
+		t.Error("expected undecodable content to fail")
+	}
+}
+
+func TestTirEnvelopeCanonical(t *testing.T) {
+	cases := []struct {
+		name string
+		tir  core.TirEnvelope
+		want string
+	}{
+		{"lenient hex labelled base64", core.TirEnvelope{Content: "aabbcc", Encoding: "base64", Lenient: true}, "hex"},
+		{"lenient real base64", core.TirEnvelope{Content: "qrvM", Encoding: "base64", Lenient: true}, "base64"},
+		{"strict hex labelled base64", core.TirEnvelope{Content: "aabbcc", Encoding: "base64"}, "base64"},
+		{"hex", core.TirEnvelope{Content: "aabbcc", Encoding: "hex", Lenient: true}, "hex"},
+	}
+	for _, tc := range cases {
+		if got := tc.tir.Canonical().Encoding; got != tc.want {
+			t.Errorf("%s: expected encoding %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
@@ -5,6 +5,10 @@ package core
 // Keys are matched case-insensitively against protocol-declared parameter names.
 type ArgMap map[string]interface{}
 
+// ArgList holds transaction arguments in declaration order, for TIRs
+// compiled with positional parameters.
+type ArgList []interface{}
+
 // EnvMap holds environment variables for a TRP invocation.
 type EnvMap map[string]interface{}
 
@@ -33,4 +37,14 @@ type TirEnvelope struct {
 	Content  string `json:"content"`
 	Encoding string `json:"encoding"`
 	Version  string `json:"version"`
+
+	// Hash, when set, identifies the bytecode to servers that cache compiled
+	// TIR, letting them skip recompiling it; other servers ignore it. See
+	// ComputeHash.
+	Hash string `json:"hash,omitempty"`
+
+	// Lenient makes DecodeContent fall back to hex when content labelled
+	// base64 fails to decode, for producers that mislabel their output. It is
+	// not sent over the wire; the TRP client sends Canonical() instead.
+	Lenient bool `json:"-"`
 }
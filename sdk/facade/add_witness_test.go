@@ -10,6 +10,7 @@ import (
 	"github.com/tx3-lang/go-sdk/sdk/core"
 	"github.com/tx3-lang/go-sdk/sdk/facade"
 	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
 )
 
 func fakeWitness(pubHex, sigHex string) trp.TxWitness {
@@ -25,6 +26,7 @@ func recordingTRPServer(t *testing.T) (*httptest.Server, *trp.Client, *trp.Submi
 	t.Helper()
 	var captured trp.SubmitParams
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		method := ""
@@ -33,7 +35,7 @@ func recordingTRPServer(t *testing.T) (*httptest.Server, *trp.Client, *trp.Submi
 		switch method {
 		case "trp.resolve":
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0", "id": "1",
+				"jsonrpc": "2.0", "id": id,
 				"result": map[string]interface{}{
 					"hash": "deadbeef00000000000000000000000000000000000000000000000000000000",
 					"tx":   "84a40081",
@@ -44,7 +46,7 @@ func recordingTRPServer(t *testing.T) (*httptest.Server, *trp.Client, *trp.Submi
 			json.Unmarshal(req["params"], &params)
 			captured = params
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0", "id": "1",
+				"jsonrpc": "2.0", "id": id,
 				"result": map[string]interface{}{
 					"hash": "deadbeef00000000000000000000000000000000000000000000000000000000",
 				},
@@ -178,7 +178,7 @@ func (b *Tx3ClientBuilder) Build() (*Tx3Client, error) {
 	if b.trpClientOverride != nil {
 		trpClient = b.trpClientOverride
 	} else {
-		if b.trpOptions == nil || b.trpOptions.Endpoint == "" {
+		if b.trpOptions == nil || (b.trpOptions.Endpoint == "" && len(b.trpOptions.Endpoints) == 0 && len(b.trpOptions.EndpointConfigs) == 0) {
 			return nil, &MissingTrpEndpointError{}
 		}
 		trpClient = trp.NewClient(*b.trpOptions)
@@ -13,6 +13,7 @@ import (
 	"github.com/tx3-lang/go-sdk/sdk/signer"
 	"github.com/tx3-lang/go-sdk/sdk/tii"
 	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
 )
 
 func newTestProtocol(t *testing.T) *tii.Protocol {
@@ -27,6 +28,7 @@ func newTestProtocol(t *testing.T) *tii.Protocol {
 func newMockTRPServer(t *testing.T) (*httptest.Server, *trp.Client) {
 	t.Helper()
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		method := ""
@@ -36,7 +38,7 @@ func newMockTRPServer(t *testing.T) (*httptest.Server, *trp.Client) {
 		case "trp.resolve":
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"jsonrpc": "2.0",
-				"id":      "1",
+				"id":      id,
 				"result": map[string]interface{}{
 					"hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
 					"tx":   "deadbeefcafebabe",
@@ -45,7 +47,7 @@ func newMockTRPServer(t *testing.T) (*httptest.Server, *trp.Client) {
 		case "trp.submit":
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"jsonrpc": "2.0",
-				"id":      "1",
+				"id":      id,
 				"result": map[string]interface{}{
 					"hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
 				},
@@ -53,7 +55,7 @@ func newMockTRPServer(t *testing.T) (*httptest.Server, *trp.Client) {
 		case "trp.checkStatus":
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"jsonrpc": "2.0",
-				"id":      "1",
+				"id":      id,
 				"result": map[string]interface{}{
 					"statuses": map[string]interface{}{
 						"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855": map[string]interface{}{
@@ -88,6 +90,19 @@ func TestBuilder_MissingEndpoint(t *testing.T) {
 	}
 }
 
+func TestBuilder_EndpointConfigsOnly(t *testing.T) {
+	server, _ := newMockTRPServer(t)
+	defer server.Close()
+
+	_, err := facade.FromProtocol(newTestProtocol(t)).
+		TRP(trp.ClientOptions{EndpointConfigs: []trp.EndpointConfig{{URL: server.URL}}}).
+		WithProfile("preprod").
+		Build()
+	if err != nil {
+		t.Fatalf("expected EndpointConfigs to satisfy the endpoint check, got %v", err)
+	}
+}
+
 func TestBuilder_UnknownProfile(t *testing.T) {
 	server, _ := newMockTRPServer(t)
 	defer server.Close()
@@ -173,6 +188,7 @@ func TestBuiltClient_WithParty_RejectsUnknown(t *testing.T) {
 func TestBuilder_WithEnvValue_OverridesProfileEnv(t *testing.T) {
 	var receivedArgs map[string]interface{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		var params map[string]json.RawMessage
@@ -180,7 +196,7 @@ func TestBuilder_WithEnvValue_OverridesProfileEnv(t *testing.T) {
 		json.Unmarshal(params["args"], &receivedArgs)
 
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"jsonrpc": "2.0", "id": "1",
+			"jsonrpc": "2.0", "id": id,
 			"result": map[string]interface{}{"hash": "abc", "tx": "def"},
 		})
 	}))
@@ -212,17 +228,14 @@ func TestBuilder_WithEnvValue_OverridesProfileEnv(t *testing.T) {
 func TestPartyAddressInjection(t *testing.T) {
 	var receivedArgs map[string]interface{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		var params map[string]json.RawMessage
 		json.Unmarshal(req["params"], &params)
 		json.Unmarshal(params["args"], &receivedArgs)
 
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"id":      "1",
-			"result":  map[string]interface{}{"hash": "abc", "tx": "def"},
-		})
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "def"})
 	}))
 	defer server.Close()
 
@@ -316,6 +329,7 @@ func TestFullBuilderChainWithMockSigner(t *testing.T) {
 func TestFromParts_CodegenFlow(t *testing.T) {
 	var receivedArgs map[string]interface{}
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		var params map[string]json.RawMessage
@@ -323,7 +337,7 @@ func TestFromParts_CodegenFlow(t *testing.T) {
 		json.Unmarshal(params["args"], &receivedArgs)
 
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"jsonrpc": "2.0", "id": "1",
+			"jsonrpc": "2.0", "id": id,
 			"result": map[string]interface{}{"hash": "abc", "tx": "def"},
 		})
 	}))
@@ -359,6 +373,7 @@ func TestFromParts_CodegenFlow(t *testing.T) {
 
 func TestSubmitHashMismatch(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		method := ""
@@ -368,18 +383,14 @@ func TestSubmitHashMismatch(t *testing.T) {
 		case "trp.resolve":
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"jsonrpc": "2.0",
-				"id":      "1",
+				"id":      id,
 				"result": map[string]interface{}{
 					"hash": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
 					"tx":   "deadbeef",
 				},
 			})
 		case "trp.submit":
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0",
-				"id":      "1",
-				"result":  map[string]interface{}{"hash": "different_hash"},
-			})
+			trptest.WriteResult(w, id, map[string]interface{}{"hash": "different_hash"})
 		}
 	}))
 	defer server.Close()
@@ -411,6 +422,7 @@ func TestSubmitHashMismatch(t *testing.T) {
 
 func TestWaitForConfirmedTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		method := ""
@@ -418,21 +430,13 @@ func TestWaitForConfirmedTimeout(t *testing.T) {
 
 		switch method {
 		case "trp.resolve":
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0",
-				"id":      "1",
-				"result":  map[string]interface{}{"hash": "abc", "tx": "def"},
-			})
+			trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "def"})
 		case "trp.submit":
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0",
-				"id":      "1",
-				"result":  map[string]interface{}{"hash": "abc"},
-			})
+			trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc"})
 		case "trp.checkStatus":
 			json.NewEncoder(w).Encode(map[string]interface{}{
 				"jsonrpc": "2.0",
-				"id":      "1",
+				"id":      id,
 				"result": map[string]interface{}{
 					"statuses": map[string]interface{}{
 						"abc": map[string]interface{}{
@@ -473,6 +477,7 @@ func TestWaitForConfirmedTimeout(t *testing.T) {
 
 func TestWaitForConfirmedDropped(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		method := ""
@@ -481,17 +486,17 @@ func TestWaitForConfirmedDropped(t *testing.T) {
 		switch method {
 		case "trp.resolve":
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0", "id": "1",
+				"jsonrpc": "2.0", "id": id,
 				"result": map[string]interface{}{"hash": "abc", "tx": "def"},
 			})
 		case "trp.submit":
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0", "id": "1",
+				"jsonrpc": "2.0", "id": id,
 				"result": map[string]interface{}{"hash": "abc"},
 			})
 		case "trp.checkStatus":
 			json.NewEncoder(w).Encode(map[string]interface{}{
-				"jsonrpc": "2.0", "id": "1",
+				"jsonrpc": "2.0", "id": id,
 				"result": map[string]interface{}{
 					"statuses": map[string]interface{}{
 						"abc": map[string]interface{}{
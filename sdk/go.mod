@@ -4,9 +4,14 @@ go 1.24.2
 
 require (
 	filippo.io/edwards25519 v1.1.0
+	github.com/coder/websocket v1.8.13
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/google/uuid v1.6.0
 	github.com/tyler-smith/go-bip39 v1.1.0
 	golang.org/x/crypto v0.37.0
 )
 
-require golang.org/x/sys v0.32.0 // indirect
+require (
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+)
@@ -0,0 +1,22 @@
+package trp
+
+import "context"
+
+// ResolveResult is the outcome of an asynchronous resolve.
+type ResolveResult struct {
+	Envelope *TxEnvelope
+	Err      error
+}
+
+// ResolveAsync runs Resolve in a new goroutine. The returned channel is
+// buffered, receives exactly one result and is then closed, so callers may
+// abandon it without leaking the goroutine.
+func (c *Client) ResolveAsync(ctx context.Context, params ResolveParams, opts ...CallOption) <-chan ResolveResult {
+	ch := make(chan ResolveResult, 1)
+	go func() {
+		defer close(ch)
+		envelope, err := c.Resolve(ctx, params, opts...)
+		ch <- ResolveResult{Envelope: envelope, Err: err}
+	}()
+	return ch
+}
@@ -0,0 +1,42 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+func TestResolveAsync(t *testing.T) {
+	server, _ := flakyServer(t, 0, http.StatusOK)
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+
+	results := []<-chan trp.ResolveResult{
+		client.ResolveAsync(context.Background(), testParams()),
+		client.ResolveAsync(context.Background(), testParams()),
+	}
+	for i, ch := range results {
+		res := <-ch
+		if res.Err != nil {
+			t.Fatalf("result %d failed: %v", i, res.Err)
+		}
+		if res.Envelope.Hash != "abc" {
+			t.Errorf("result %d: unexpected hash %q", i, res.Envelope.Hash)
+		}
+		if _, ok := <-ch; ok {
+			t.Errorf("result %d: expected channel to be closed", i)
+		}
+	}
+}
+
+func TestResolveAsyncError(t *testing.T) {
+	client := trp.NewClient(trp.ClientOptions{Endpoint: "http://127.0.0.1:1"})
+
+	res := <-client.ResolveAsync(context.Background(), trp.ResolveParams{})
+	var tirErr *trp.InvalidTirError
+	if !errors.As(res.Err, &tirErr) || res.Envelope != nil {
+		t.Fatalf("expected InvalidTirError and no envelope, got %+v", res)
+	}
+}
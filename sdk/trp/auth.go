@@ -0,0 +1,58 @@
+package trp
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+)
+
+// BasicAuth holds HTTP basic authentication credentials.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// applyAuth sets the Authorization header from the configured credentials.
+// TokenProvider wins over BearerToken, which wins over BasicAuth, which wins
+// over a static header.
+func (c *Client) applyAuth(ctx context.Context, header http.Header) error {
+	if basic := c.options.BasicAuth; basic != nil {
+		header.Set("Authorization", basicAuthorization(basic))
+	}
+	token := c.options.BearerToken
+	if c.options.TokenProvider != nil {
+		t, err := c.options.TokenProvider(ctx)
+		if err != nil {
+			return &TokenProviderError{Cause: err}
+		}
+		token = t
+	}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// applyEndpointConfig sets the headers and credentials of the
+// EndpointConfigs entry for endpoint, if any, over the client-wide ones.
+func (c *Client) applyEndpointConfig(endpoint string, header http.Header) {
+	for _, config := range c.options.EndpointConfigs {
+		if config.URL != endpoint {
+			continue
+		}
+		for k, v := range config.Headers {
+			header.Set(k, v)
+		}
+		switch {
+		case config.BearerToken != "":
+			header.Set("Authorization", "Bearer "+config.BearerToken)
+		case config.BasicAuth != nil:
+			header.Set("Authorization", basicAuthorization(config.BasicAuth))
+		}
+		return
+	}
+}
+
+func basicAuthorization(basic *BasicAuth) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(basic.Username+":"+basic.Password))
+}
@@ -0,0 +1,177 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ResolveBatch resolves several transactions in a single HTTP round trip
+// using a JSON-RPC batch request (one trp.resolve call per element).
+//
+// Responses are correlated back to their requests by id, so the returned
+// slices are index-aligned with params regardless of the order the server
+// answers in. Per-item failures are reported in the []error slice (with a nil
+// envelope); a transport-level failure, or an invalid TIR envelope in any
+// item, is returned as the final error.
+//
+// With BatchSharedEnv set, servers that implement the trp.resolveBatch
+// extension get a single request carrying the env common to all items once.
+//
+// With MaxBatchSize set, params are sent in sub-batches of at most that many
+// items, one after another. Once the time left before ctx's deadline is
+// shorter than the slowest sub-batch so far, the remaining items are not
+// sent and fail with *BatchDeadlineError. A transport-level failure stops
+// the batch too: the slices are then returned alongside the final error,
+// holding the results of the sub-batches that completed and that error for
+// every other item.
+func (c *Client) ResolveBatch(ctx context.Context, params []ResolveParams, opts ...CallOption) ([]*TxEnvelope, []error, error) {
+	if len(params) == 0 {
+		return nil, nil, nil
+	}
+	if err := c.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+
+	env, err := c.defaultEnv(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	co := newCallOptions(opts)
+	prepared := make([]ResolveParams, len(params))
+	for i, p := range params {
+		if prepared[i], err = c.prepareResolve(p, env); err != nil {
+			return nil, nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		prepared[i] = co.withLabels(prepared[i])
+	}
+	size := c.options.MaxBatchSize
+	if size <= 0 || size >= len(prepared) {
+		return c.resolveSubBatch(ctx, prepared, opts)
+	}
+
+	envelopes := make([]*TxEnvelope, len(prepared))
+	errs := make([]error, len(prepared))
+	var slowest time.Duration
+	for start := 0; start < len(prepared); start += size {
+		end := min(start+size, len(prepared))
+		if deadline, ok := ctx.Deadline(); ok && start > 0 {
+			if remaining := time.Until(deadline); remaining < slowest {
+				for i := start; i < len(prepared); i++ {
+					errs[i] = &BatchDeadlineError{Remaining: remaining}
+				}
+				return envelopes, errs, nil
+			}
+		}
+
+		sent := time.Now()
+		subEnvelopes, subErrs, err := c.resolveSubBatch(ctx, prepared[start:end], opts)
+		if err != nil {
+			for i := start; i < len(prepared); i++ {
+				errs[i] = err
+			}
+			return envelopes, errs, err
+		}
+		slowest = max(slowest, time.Since(sent))
+		copy(envelopes[start:], subEnvelopes)
+		copy(errs[start:], subErrs)
+	}
+	return envelopes, errs, nil
+}
+
+// resolveSubBatch sends prepared params as one request.
+func (c *Client) resolveSubBatch(ctx context.Context, prepared []ResolveParams, opts []CallOption) ([]*TxEnvelope, []error, error) {
+	if c.options.BatchSharedEnv && c.resolveBatchSupported(ctx) {
+		return c.resolveBatchShared(ctx, prepared, opts)
+	}
+
+	method := c.resolveMethod()
+	requests := make([]jsonRPCRequest, len(prepared))
+	index := make(map[string]int, len(prepared))
+	for i, p := range prepared {
+		requests[i] = c.newRequest(method, p)
+		index[requests[i].ID] = i
+	}
+
+	bodyBytes, err := c.codec().Marshal(requests)
+	if err != nil {
+		return nil, nil, &NetworkError{Cause: fmt.Errorf("failed to marshal request: %w", err)}
+	}
+	if err := c.checkRequestSize(bodyBytes); err != nil {
+		return nil, nil, err
+	}
+
+	out := outgoing{method: method, id: fmt.Sprintf("batch[%d]", len(requests)), body: bodyBytes, idempotent: true, call: newCallOptions(opts)}
+
+	ctx, leave, err := c.enter(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer leave()
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+	ctx, finish := c.startCall(ctx, out)
+	var responses []jsonRPCResponse
+	err = c.retry(ctx, true, func() error {
+		respBody, err := c.deliver(ctx, out)
+		if err != nil {
+			return err
+		}
+		responses, err = c.decodeBatchResponse(respBody)
+		return err
+	})
+	finish(nil, err)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envelopes := make([]*TxEnvelope, len(prepared))
+	errs := make([]error, len(prepared))
+	seen := make([]bool, len(prepared))
+	for i := range responses {
+		resp := &responses[i]
+		pos, ok := index[resp.ID]
+		if !ok || seen[pos] {
+			continue
+		}
+		seen[pos] = true
+
+		err := c.checkResponse(resp, requests[pos].ID)
+		var result json.RawMessage
+		if err == nil {
+			result, err = resp.result(c.options.ErrorFormatter)
+		}
+		if err == nil {
+			envelopes[pos], err = c.resolved(result)
+		}
+		errs[pos] = err
+	}
+	for i := range seen {
+		if !seen[i] {
+			errs[i] = &MalformedResponseError{Detail: fmt.Sprintf("batch response has no entry for request id %s", requests[i].ID)}
+		}
+	}
+
+	return envelopes, errs, nil
+}
+
+// decodeBatchResponse parses a JSON-RPC batch response. Servers that reject
+// the batch as a whole answer with a single error object instead of an array;
+// that error is returned as-is.
+func (c *Client) decodeBatchResponse(respBody []byte) ([]jsonRPCResponse, error) {
+	var responses []jsonRPCResponse
+	if err := c.codec().Unmarshal(respBody, &responses); err == nil {
+		return responses, nil
+	}
+
+	var single jsonRPCResponse
+	if err := c.codec().Unmarshal(respBody, &single); err != nil {
+		return nil, &DeserializationError{Cause: err, Raw: string(respBody)}
+	}
+	if single.Error != nil {
+		return nil, classifyRpcError(single.Error, c.options.ErrorFormatter)
+	}
+	return nil, &MalformedResponseError{Detail: "batch response is not an array"}
+}
@@ -0,0 +1,314 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+func TestResolveBatchCorrelatesById(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		var reqs []struct {
+			ID     string `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Args map[string]interface{} `json:"args"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			t.Errorf("expected batch array: %v", err)
+			return
+		}
+
+		// Answer in reverse order; the second item fails.
+		var out []map[string]interface{}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			req := reqs[i]
+			if req.Method != "trp.resolve" {
+				t.Errorf("unexpected method %q", req.Method)
+			}
+			name, _ := req.Params.Args["name"].(string)
+			if name == "bad" {
+				out = append(out, map[string]interface{}{
+					"jsonrpc": "2.0",
+					"id":      req.ID,
+					"error":   map[string]interface{}{"code": -32000, "message": "boom"},
+				})
+				continue
+			}
+			out = append(out, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  map[string]interface{}{"hash": name, "tx": "beef"},
+			})
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	envelopes, errs, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{
+		{Tir: testTir, Args: map[string]interface{}{"name": "first"}},
+		{Tir: testTir, Args: map[string]interface{}{"name": "bad"}},
+		{Tir: testTir, Args: map[string]interface{}{"name": "third"}},
+	})
+	if err != nil {
+		t.Fatalf("ResolveBatch failed: %v", err)
+	}
+	if requestCount != 1 {
+		t.Errorf("expected a single HTTP request, got %d", requestCount)
+	}
+	if envelopes[0] == nil || envelopes[0].Hash != "first" {
+		t.Errorf("expected first envelope hash 'first', got %+v", envelopes[0])
+	}
+	if envelopes[2] == nil || envelopes[2].Hash != "third" {
+		t.Errorf("expected third envelope hash 'third', got %+v", envelopes[2])
+	}
+	if envelopes[1] != nil {
+		t.Errorf("expected nil envelope for failed item, got %+v", envelopes[1])
+	}
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(errs[1], &rpcErr) || rpcErr.Code != -32000 {
+		t.Errorf("expected GenericRpcError -32000 for failed item, got %v", errs[1])
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("expected no errors for successful items, got %v", errs)
+	}
+}
+
+func TestResolveBatchTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, _, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{testParams()})
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HttpError, got %T: %v", err, err)
+	}
+}
+
+func TestResolveBatchSharedEnv(t *testing.T) {
+	var probes, batches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     string `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Env   map[string]interface{} `json:"env"`
+				Items []struct {
+					Args map[string]interface{} `json:"args"`
+					Env  map[string]interface{} `json:"env"`
+				} `json:"items"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("expected a single request: %v", err)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "trp.capabilities":
+			probes++
+			resp["result"] = map[string]interface{}{"methods": []string{"trp.resolve", "trp.resolveBatch"}}
+		case "trp.resolveBatch":
+			batches++
+			if req.Params.Env["network"] != "preview" || req.Params.Env["slot"] != 1.0 || len(req.Params.Env) != 2 {
+				t.Errorf("expected shared env {network: preview, slot: 1}, got %v", req.Params.Env)
+			}
+			var items []map[string]interface{}
+			for _, item := range req.Params.Items {
+				if len(item.Env) != 0 {
+					t.Errorf("shared env key repeated in item: %v", item.Env)
+				}
+				name, _ := item.Args["name"].(string)
+				if name == "bad" {
+					items = append(items, map[string]interface{}{"error": map[string]interface{}{"code": -32000, "message": "boom"}})
+					continue
+				}
+				items = append(items, map[string]interface{}{"result": map[string]interface{}{"hash": name, "tx": "beef"}})
+			}
+			resp["result"] = items
+		default:
+			t.Errorf("unexpected method %q", req.Method)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithBatchSharedEnv(), trp.WithEnvArg("network", "preview"))
+	params := []trp.ResolveParams{
+		{Tir: testTir, Args: map[string]interface{}{"name": "first"}, Env: map[string]interface{}{"slot": 1}},
+		{Tir: testTir, Args: map[string]interface{}{"name": "bad"}, Env: map[string]interface{}{"slot": 1.0}},
+	}
+	for i := 0; i < 2; i++ {
+		envelopes, errs, err := client.ResolveBatch(context.Background(), params)
+		if err != nil {
+			t.Fatalf("ResolveBatch failed: %v", err)
+		}
+		if envelopes[0] == nil || envelopes[0].Hash != "first" || errs[0] != nil {
+			t.Errorf("unexpected first item: %+v, %v", envelopes[0], errs[0])
+		}
+		var rpcErr *trp.GenericRpcError
+		if !errors.As(errs[1], &rpcErr) || rpcErr.Code != -32000 {
+			t.Errorf("expected GenericRpcError -32000 for failed item, got %v", errs[1])
+		}
+	}
+	if probes != 1 || batches != 2 {
+		t.Errorf("expected 1 probe and 2 batch calls, got %d and %d", probes, batches)
+	}
+	if _, ok := params[0].Env["network"]; ok {
+		t.Errorf("caller env was modified: %v", params[0].Env)
+	}
+}
+
+func TestResolveBatchSharedEnvFallsBack(t *testing.T) {
+	var batchRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 || body[0] != '[' {
+			var req struct {
+				ID string `json:"id"`
+			}
+			json.Unmarshal(body, &req)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": req.ID,
+				"error": map[string]interface{}{"code": trp.ErrCodeMethodNotFound, "message": "method not found"},
+			})
+			return
+		}
+		batchRequests++
+		var reqs []struct {
+			ID     string `json:"id"`
+			Params struct {
+				Env map[string]interface{} `json:"env"`
+			} `json:"params"`
+		}
+		json.Unmarshal(body, &reqs)
+		var out []map[string]interface{}
+		for _, req := range reqs {
+			if req.Params.Env["network"] != "preview" {
+				t.Errorf("expected env on every item, got %v", req.Params.Env)
+			}
+			out = append(out, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": map[string]interface{}{"hash": "h", "tx": "beef"}})
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithBatchSharedEnv(), trp.WithEnvArg("network", "preview"))
+	_, errs, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{testParams(), testParams()})
+	if err != nil {
+		t.Fatalf("ResolveBatch failed: %v", err)
+	}
+	if errs[0] != nil || errs[1] != nil {
+		t.Errorf("unexpected item errors: %v", errs)
+	}
+	if batchRequests != 1 {
+		t.Errorf("expected a plain JSON-RPC batch, got %d", batchRequests)
+	}
+}
+
+func TestResolveBatchSharedEnvRetriesFailedProbe(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if body[0] != '[' {
+			probes.Add(1)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		var reqs []struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(body, &reqs)
+		var out []map[string]interface{}
+		for _, req := range reqs {
+			out = append(out, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": map[string]interface{}{"hash": "h", "tx": "beef"}})
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := trp.NewClientWithOptions(server.URL, trp.WithBatchSharedEnv(), trp.WithClock(clock))
+	resolve := func() {
+		if _, _, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{testParams()}); err != nil {
+			t.Fatalf("ResolveBatch failed: %v", err)
+		}
+	}
+	resolve()
+	resolve()
+	if probes.Load() != 1 {
+		t.Fatalf("expected a failed probe to be cached briefly, got %d probes", probes.Load())
+	}
+	clock.Advance(time.Minute)
+	resolve()
+	if probes.Load() != 2 {
+		t.Errorf("expected the probe to be retried once the failure lapsed, got %d probes", probes.Load())
+	}
+}
+
+func TestResolveBatchMaxBatchSizeStopsNearDeadline(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("expected a JSON-RPC batch: %v", err)
+		}
+		mu.Lock()
+		sizes = append(sizes, len(requests))
+		mu.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		responses := make([]map[string]interface{}, len(requests))
+		for i, req := range requests {
+			responses[i] = map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": map[string]interface{}{"hash": "abc", "tx": "beef"}}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	client := trp.NewClientWithOptions(server.URL, trp.WithMaxBatchSize(2))
+	params := make([]trp.ResolveParams, 5)
+	for i := range params {
+		params[i] = testParams()
+	}
+	envelopes, errs, err := client.ResolveBatch(ctx, params)
+	if err != nil {
+		t.Fatalf("ResolveBatch failed: %v", err)
+	}
+
+	// Two sub-batches take ~200ms; with ~50ms left, the third is not sent.
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+		t.Errorf("expected two sub-batches of 2, got %v", sizes)
+	}
+	for i := 0; i < 4; i++ {
+		if errs[i] != nil || envelopes[i] == nil {
+			t.Errorf("item %d: expected an envelope, got %v", i, errs[i])
+		}
+	}
+	var deadlineErr *trp.BatchDeadlineError
+	if !errors.As(errs[4], &deadlineErr) || !trp.IsTimeout(errs[4]) || envelopes[4] != nil {
+		t.Errorf("expected BatchDeadlineError for the unsent item, got %v", errs[4])
+	}
+}
@@ -0,0 +1,173 @@
+package trp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// methodResolveBatch is the TRP extension that resolves several
+// transactions in one call, sending the env they share only once. Servers
+// implementing it list it in trp.capabilities.
+const methodResolveBatch = "trp.resolveBatch"
+
+// resolveBatchParams is the request body for trp.resolveBatch. Each item's
+// env is merged over Env by the server, the item winning on conflict.
+type resolveBatchParams struct {
+	Env   map[string]interface{} `json:"env,omitempty"`
+	Items []ResolveParams        `json:"items"`
+}
+
+// resolveBatchItem is one element of the trp.resolveBatch result, aligned
+// with the request items.
+type resolveBatchItem struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+// probeFailureTTL is how long a failed extension probe (e.g. a network
+// error) is remembered as "unsupported" before the next batch tries again.
+const probeFailureTTL = 30 * time.Second
+
+// extensionProbe caches whether the server supports a TRP extension. At most
+// one probe runs at a time; other callers wait for it, or for their own
+// context to end.
+type extensionProbe struct {
+	mu        sync.Mutex
+	known     bool          // Whether supported holds an answer
+	supported bool          // The cached answer
+	expires   time.Time     // When a failure-derived answer lapses; zero if it never does
+	running   chan struct{} // Closed when the probe in progress finishes; nil if none is
+}
+
+// resolveBatchSupported reports whether the server advertises
+// trp.resolveBatch. A definite answer is cached for the client's lifetime;
+// a failed probe counts as unsupported for probeFailureTTL.
+func (c *Client) resolveBatchSupported(ctx context.Context) bool {
+	p := &c.resolveBatchProbe
+	for {
+		p.mu.Lock()
+		if p.known && (p.expires.IsZero() || c.clock().Now().Before(p.expires)) {
+			defer p.mu.Unlock()
+			return p.supported
+		}
+		if running := p.running; running != nil {
+			p.mu.Unlock()
+			select {
+			case <-running:
+				continue
+			case <-ctx.Done():
+				return false
+			}
+		}
+		running := make(chan struct{})
+		p.running = running
+		p.mu.Unlock()
+
+		caps, err := c.Capabilities(ctx)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.running = nil
+		close(running)
+		var unsupported *UnsupportedMethodError
+		switch {
+		case err == nil:
+			p.known, p.supported, p.expires = true, caps.Supports(methodResolveBatch), time.Time{}
+		case errors.As(err, &unsupported):
+			p.known, p.supported, p.expires = true, false, time.Time{}
+		case ctx.Err() == nil:
+			// Only this caller's context ending says nothing about the server.
+			p.known, p.supported, p.expires = true, false, c.clock().Now().Add(probeFailureTTL)
+		}
+		return p.known && p.supported
+	}
+}
+
+// resolveBatchShared resolves already prepared params with one
+// trp.resolveBatch call.
+func (c *Client) resolveBatchShared(ctx context.Context, params []ResolveParams, opts []CallOption) ([]*TxEnvelope, []error, error) {
+	shared, items := splitSharedEnv(params)
+	co := newCallOptions(opts)
+	co.streamBody = true
+	result, err := c.call(ctx, methodResolveBatch, resolveBatchParams{Env: shared, Items: items}, true, co)
+	if err != nil {
+		return nil, nil, err
+	}
+	var answers []resolveBatchItem
+	if err := json.Unmarshal(result, &answers); err != nil {
+		return nil, nil, &DeserializationError{Cause: err, Raw: string(result)}
+	}
+	if len(answers) != len(params) {
+		return nil, nil, &MalformedResponseError{Detail: fmt.Sprintf("%s returned %d results for %d items", methodResolveBatch, len(answers), len(params))}
+	}
+
+	envelopes := make([]*TxEnvelope, len(params))
+	errs := make([]error, len(params))
+	for i, answer := range answers {
+		switch {
+		case answer.Error != nil:
+			errs[i] = classifyRpcError(answer.Error, c.options.ErrorFormatter)
+		case answer.Result == nil:
+			errs[i] = &MalformedResponseError{Detail: fmt.Sprintf("%s item %d has no result", methodResolveBatch, i)}
+		default:
+			envelopes[i], errs[i] = c.resolved(answer.Result)
+		}
+	}
+	return envelopes, errs, nil
+}
+
+// splitSharedEnv moves the env entries that every item has, with equal
+// JSON encodings, into a shared env. It returns copies of params holding only the
+// remaining per-item entries; the caller's maps are not modified.
+func splitSharedEnv(params []ResolveParams) (map[string]interface{}, []ResolveParams) {
+	shared := make(map[string]interface{})
+	for k, v := range params[0].Env {
+		want, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		common := true
+		for _, p := range params[1:] {
+			other, ok := p.Env[k]
+			if !ok {
+				common = false
+				break
+			}
+			// Compare encodings: the server only ever sees those, and they
+			// equate values such as int(1) and float64(1).
+			got, err := json.Marshal(other)
+			if err != nil || !bytes.Equal(want, got) {
+				common = false
+				break
+			}
+		}
+		if common {
+			shared[k] = v
+		}
+	}
+
+	items := make([]ResolveParams, len(params))
+	for i, p := range params {
+		var own map[string]interface{}
+		for k, v := range p.Env {
+			if _, ok := shared[k]; ok {
+				continue
+			}
+			if own == nil {
+				own = make(map[string]interface{})
+			}
+			own[k] = v
+		}
+		p.Env = own
+		items[i] = p
+	}
+	if len(shared) == 0 {
+		shared = nil
+	}
+	return shared, items
+}
@@ -0,0 +1,88 @@
+package trp
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// bodyEncoder is implemented by codecs that can write a message
+// incrementally instead of marshalling it into memory first.
+type bodyEncoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error { return json.NewEncoder(w).Encode(v) }
+
+// canStream reports whether request bodies can be generated on the fly:
+// StreamRequests is set and the codec supports it. A Signer,
+// RequestRecorder or MaxRequestBytes needs the complete body up front, so
+// it forces buffering.
+func (c *Client) canStream() bool {
+	if !c.options.StreamRequests || c.options.Signer != nil || c.options.RequestRecorder != nil ||
+		c.options.MaxRequestBytes > 0 {
+		return false
+	}
+	_, ok := c.codec().(bodyEncoder)
+	return ok
+}
+
+// streamedBody is a request body written by a goroutine as the transport
+// reads it, so the encoded message is never held in memory as a whole.
+type streamedBody struct {
+	*io.PipeReader
+	err atomic.Pointer[error] // encoding failure, if any
+}
+
+// streamBody starts encoding v (gzipped if configured) into a new body.
+// Every attempt gets a fresh body, which is what lets retries, failover and
+// redirects regenerate the payload. The goroutine ends once the transport
+// has read or closed the body.
+func (c *Client) streamBody(v interface{}) *streamedBody {
+	pr, pw := io.Pipe()
+	b := &streamedBody{PipeReader: pr}
+	enc := c.codec().(bodyEncoder)
+	go func() {
+		err := c.encodeBody(pw, enc, v)
+		if err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			b.err.Store(&err)
+		}
+		pw.CloseWithError(err)
+	}()
+	return b
+}
+
+func (c *Client) encodeBody(w io.Writer, enc bodyEncoder, v interface{}) error {
+	if !c.options.Compression {
+		return enc.Encode(w, v)
+	}
+	zw := gzip.NewWriter(w)
+	if err := enc.Encode(zw, v); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// failure returns the error to report if the body could not be generated,
+// or nil.
+func (b *streamedBody) failure() error {
+	if err := b.err.Load(); err != nil {
+		return &NetworkError{Cause: &encodeError{cause: *err}}
+	}
+	return nil
+}
+
+// encodeError is a request that could not be marshalled. It fails the same
+// way every time, so it is neither retried nor failed over.
+type encodeError struct{ cause error }
+
+func (e *encodeError) Error() string { return fmt.Sprintf("failed to marshal request: %v", e.cause) }
+func (e *encodeError) Unwrap() error { return e.cause }
+
+func isEncodeError(err error) bool {
+	var encErr *encodeError
+	return errors.As(err, &encErr)
+}
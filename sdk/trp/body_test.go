@@ -0,0 +1,105 @@
+package trp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestResolveStreamsRequestBody(t *testing.T) {
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("expected a streamed body of unknown length, got ContentLength %d", r.ContentLength)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(body, &req)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
+		})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:       server.URL,
+		Retry:          trp.RetryOptions{MaxRetries: 1, InitialBackoff: time.Millisecond},
+		StreamRequests: true,
+	})
+	params := testParams()
+	params.Tir.Content = strings.Repeat("ab", 1<<20)
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(bodies) != 2 || !bytes.Equal(bodies[0], bodies[1]) {
+		t.Fatal("expected the retry to resend an identical body")
+	}
+	if !bytes.Contains(bodies[1], []byte(params.Tir.Content)) {
+		t.Error("expected the TIR content in the streamed body")
+	}
+}
+
+func TestRequestBodyBufferedUnlessStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		if r.ContentLength <= 0 {
+			t.Errorf("expected a buffered body with a known length, got ContentLength %d", r.ContentLength)
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	for _, options := range []trp.ClientOptions{
+		{Endpoint: server.URL},
+		{Endpoint: server.URL, StreamRequests: true, Signer: func(req *http.Request, body []byte) error { return nil }},
+	} {
+		if _, err := trp.NewClient(options).Resolve(context.Background(), testParams()); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+	}
+}
+
+func TestStreamedBodyEncodeErrorNotRetried(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:       server.URL,
+		Retry:          trp.RetryOptions{MaxRetries: 3, InitialBackoff: time.Millisecond},
+		StreamRequests: true,
+	})
+	params := testParams()
+	params.Args = map[string]interface{}{"bad": make(chan int)}
+	_, err := client.Resolve(context.Background(), params)
+	var netErr *trp.NetworkError
+	if !errors.As(err, &netErr) || !strings.Contains(err.Error(), "failed to marshal request") {
+		t.Fatalf("expected a marshal NetworkError, got %T: %v", err, err)
+	}
+	if hits.Load() > 1 {
+		t.Errorf("expected an unencodable request not to be retried; server saw %d", hits.Load())
+	}
+}
@@ -0,0 +1,176 @@
+package trp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// CircuitBreakerOptions configures the per-endpoint circuit breaker.
+//
+// The breaker counts consecutive failed HTTP attempts: transport errors,
+// per-attempt timeouts and 5xx responses. With a Window, only failures
+// within that span of the first one count towards the threshold. Any
+// response below 500 proves the server is reachable and resets the count,
+// JSON-RPC errors included. Once FailureThreshold is reached the circuit
+// opens and requests to the endpoint fail with CircuitOpenError, without
+// being sent, for Cooldown. Then the circuit is half-open: a single probe
+// request is let through, closing the circuit if it succeeds and reopening
+// it if it fails.
+type CircuitBreakerOptions struct {
+	FailureThreshold int           // Consecutive failures that open the circuit (default: 5)
+	Window           time.Duration // Span the failures must fall within; older ones are forgotten (default: no limit)
+	Cooldown         time.Duration // How long the circuit stays open (default: 30s)
+}
+
+// newCircuitBreakers creates one breaker per endpoint, or nil if options is
+// nil. The map is read-only afterwards.
+func newCircuitBreakers(endpoints []string, options *CircuitBreakerOptions, clock Clock) map[string]*circuitBreaker {
+	if options == nil {
+		return nil
+	}
+	breakers := make(map[string]*circuitBreaker, len(endpoints))
+	for _, endpoint := range endpoints {
+		breakers[endpoint] = &circuitBreaker{options: *options, clock: clock}
+	}
+	return breakers
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks the health of one endpoint. A nil *circuitBreaker
+// allows everything.
+type circuitBreaker struct {
+	options CircuitBreakerOptions
+	clock   Clock
+
+	mu           sync.Mutex
+	state        breakerState
+	failures     int       // consecutive failures while closed
+	firstFailure time.Time // start of the current streak, for Window
+	openedAt     time.Time
+	probing      bool // a half-open probe is in flight
+}
+
+func (b *circuitBreaker) threshold() int {
+	if b.options.FailureThreshold > 0 {
+		return b.options.FailureThreshold
+	}
+	return defaultBreakerThreshold
+}
+
+func (b *circuitBreaker) cooldown() time.Duration {
+	if b.options.Cooldown > 0 {
+		return b.options.Cooldown
+	}
+	return defaultBreakerCooldown
+}
+
+// allow reports whether a request to endpoint may be sent now. Every nil
+// result must be followed by a call to record.
+func (b *circuitBreaker) allow(endpoint string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		remaining := b.cooldown() - b.clock.Now().Sub(b.openedAt)
+		if remaining > 0 {
+			return &CircuitOpenError{Endpoint: endpoint, RetryAfter: remaining}
+		}
+		b.state = breakerHalfOpen
+	}
+	if b.state == breakerHalfOpen {
+		if b.probing {
+			return &CircuitOpenError{Endpoint: endpoint}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	probe := b.state == breakerHalfOpen
+	b.probing = false
+	switch breakerOutcome(ctx, err) {
+	case outcomeSuccess:
+		b.state = breakerClosed
+		b.failures = 0
+	case outcomeFailure:
+		now := b.clock.Now()
+		if probe {
+			b.state = breakerOpen
+			b.openedAt = now
+			return
+		}
+		if b.failures == 0 || (b.options.Window > 0 && now.Sub(b.firstFailure) > b.options.Window) {
+			b.failures = 0
+			b.firstFailure = now
+		}
+		b.failures++
+		if b.failures >= b.threshold() {
+			b.state = breakerOpen
+			b.openedAt = now
+			b.failures = 0
+		}
+	}
+	// outcomeNeutral leaves the state alone; a half-open breaker lets the
+	// next request probe instead.
+}
+
+type outcome int
+
+const (
+	outcomeNeutral outcome = iota
+	outcomeSuccess
+	outcomeFailure
+)
+
+// breakerOutcome classifies the result of one HTTP attempt. Failures that
+// say nothing about the server, such as the caller's context ending or a
+// request that could not be encoded, are neutral.
+func breakerOutcome(ctx context.Context, err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil || isEncodeError(err) {
+		return outcomeNeutral
+	}
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		if httpErr.Status >= 500 {
+			return outcomeFailure
+		}
+		return outcomeSuccess
+	}
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		return outcomeSuccess
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return outcomeFailure
+	}
+	return outcomeNeutral
+}
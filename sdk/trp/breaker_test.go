@@ -0,0 +1,169 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+// toggleServer answers 503 while down is set and a valid envelope otherwise.
+func toggleServer(down *atomic.Bool, hits *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	down.Store(true)
+	server := toggleServer(&down, &hits)
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithCircuitBreaker(trp.CircuitBreakerOptions{FailureThreshold: 2, Cooldown: 50 * time.Millisecond}))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var httpErr *trp.HttpError
+		if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &httpErr) {
+			t.Fatalf("attempt %d: expected HttpError, got %T: %v", i, err, err)
+		}
+	}
+	_, err := client.Resolve(ctx, testParams())
+	var openErr *trp.CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expected CircuitOpenError, got %T: %v", err, err)
+	}
+	if openErr.Endpoint != server.URL || openErr.RetryAfter <= 0 {
+		t.Errorf("unexpected CircuitOpenError: %+v", openErr)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected the open circuit to stop requests; server saw %d", hits.Load())
+	}
+
+	down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := client.Resolve(ctx, testParams()); err != nil {
+			t.Fatalf("expected recovery after cooldown, got %v", err)
+		}
+	}
+	if hits.Load() != 4 {
+		t.Errorf("expected 4 requests in total, got %d", hits.Load())
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	down.Store(true)
+	server := toggleServer(&down, &hits)
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:       server.URL,
+		CircuitBreaker: &trp.CircuitBreakerOptions{FailureThreshold: 1, Cooldown: 30 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	client.Resolve(ctx, testParams())
+	time.Sleep(40 * time.Millisecond)
+	var httpErr *trp.HttpError
+	if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &httpErr) {
+		t.Fatalf("expected the half-open probe to reach the server, got %T: %v", err, err)
+	}
+	var openErr *trp.CircuitOpenError
+	if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &openErr) {
+		t.Fatalf("expected a failed probe to reopen the circuit, got %T: %v", err, err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", hits.Load())
+	}
+}
+
+func TestCircuitBreakerIgnoresRpcErrors(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		hits.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"error":   map[string]interface{}{"code": -32000, "message": "boom"},
+		})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithCircuitBreaker(trp.CircuitBreakerOptions{FailureThreshold: 1}))
+	for i := 0; i < 3; i++ {
+		_, err := client.Resolve(context.Background(), testParams())
+		var openErr *trp.CircuitOpenError
+		if errors.As(err, &openErr) {
+			t.Fatalf("JSON-RPC errors must not open the circuit")
+		}
+	}
+	if hits.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", hits.Load())
+	}
+}
+
+func TestCircuitBreakerFailsOver(t *testing.T) {
+	var down, up atomic.Bool
+	var downHits, upHits atomic.Int32
+	down.Store(true)
+	bad := toggleServer(&down, &downHits)
+	defer bad.Close()
+	good := toggleServer(&up, &upHits)
+	defer good.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoints:      []string{bad.URL, good.URL},
+		CircuitBreaker: &trp.CircuitBreakerOptions{FailureThreshold: 1, Cooldown: time.Minute},
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+			t.Fatalf("Resolve %d failed: %v", i, err)
+		}
+	}
+	if downHits.Load() != 1 || upHits.Load() != 3 {
+		t.Errorf("expected the open endpoint to be skipped; bad=%d good=%d", downHits.Load(), upHits.Load())
+	}
+}
+
+func TestCircuitBreakerIgnoresEncodeErrors(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	server := toggleServer(&down, &hits)
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithStreamedRequests(),
+		trp.WithCircuitBreaker(trp.CircuitBreakerOptions{FailureThreshold: 1, Cooldown: time.Hour}))
+	ctx := context.Background()
+
+	bad := testParams()
+	bad.Args = map[string]interface{}{"bad": make(chan int)}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Resolve(ctx, bad); err == nil {
+			t.Fatalf("attempt %d: expected a marshal error", i)
+		}
+	}
+	if _, err := client.Resolve(ctx, testParams()); err != nil {
+		t.Fatalf("expected unencodable requests not to open the circuit, got %T: %v", err, err)
+	}
+}
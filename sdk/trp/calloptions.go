@@ -0,0 +1,111 @@
+package trp
+
+import "time"
+
+// CallOption overrides client settings for a single call. Options never
+// modify the shared Client.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout        time.Duration
+	headers        map[string]string
+	idempotencyKey string           // Sent as the Idempotency-Key header of every attempt
+	meta           *ResolveMetadata // Filled in as the call runs, for ResolveDetailed
+	labels         map[string]string
+
+	validateOnly  bool
+	streamBody    bool // Encode the request while sending it rather than up front
+	chunked       bool // Accept and assemble a chunked resolve result
+	chunkProgress func(received int)
+}
+
+// WithChunkProgress calls report with the number of transaction bytes
+// received so far, after every chunk of a chunked resolve result (see
+// ClientOptions.ChunkedResults). It runs on the goroutine reading the
+// response and must not block.
+func WithChunkProgress(report func(received int)) CallOption {
+	return func(o *callOptions) {
+		o.chunkProgress = report
+	}
+}
+
+// WithCallTimeout replaces ClientOptions.Timeout for each HTTP attempt of
+// this call.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithCallHeader adds a header to this call. Call headers are merged over the
+// client's Headers and credentials, winning on conflict.
+func WithCallHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// WithIdempotencyKey sends key as the Idempotency-Key header of this call,
+// so the server can recognise a retried submission and not act on it twice.
+// Every attempt of the call, retries and failover included, carries the
+// same key; use a fresh one for each logical operation. A WithCallHeader
+// for Idempotency-Key takes precedence. Like other per-call headers, it is
+// not sent by WebSocketClient.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithValidateOnly turns a resolve into a dry run: the server validates the
+// TIR and args without producing a transaction, and Resolve returns a nil
+// envelope on success. See also Client.Validate.
+func WithValidateOnly() CallOption {
+	return func(o *callOptions) {
+		o.validateOnly = true
+	}
+}
+
+// WithLabels attaches correlation labels (e.g. request origin or user id)
+// to a resolve, sent in the meta field of its params for the server to log.
+// They travel apart from the protocol fields and from headers, and are
+// merged over any ResolveParams.Meta, winning on conflict. Labels apply to
+// every item of a ResolveBatch.
+func WithLabels(labels map[string]string) CallOption {
+	return func(o *callOptions) {
+		if o.labels == nil {
+			o.labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			o.labels[k] = v
+		}
+	}
+}
+
+// withLabels returns params with the call's labels merged into a copy of
+// its Meta.
+func (o callOptions) withLabels(params ResolveParams) ResolveParams {
+	if len(o.labels) == 0 {
+		return params
+	}
+	meta := make(map[string]string, len(params.Meta)+len(o.labels))
+	for k, v := range params.Meta {
+		meta[k] = v
+	}
+	for k, v := range o.labels {
+		meta[k] = v
+	}
+	params.Meta = meta
+	return params
+}
+
+func newCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,119 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestCallHeaderMergesWithClientHeaders(t *testing.T) {
+	var got []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		got = append(got, r.Header.Clone())
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"X-Client": "a", "X-Shared": "client"},
+	})
+	_, err := client.Resolve(context.Background(), testParams(),
+		trp.WithCallHeader("X-Shared", "call"),
+		trp.WithCallHeader("X-Call", "b"),
+	)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if got[0].Get("X-Client") != "a" || got[0].Get("X-Shared") != "call" || got[0].Get("X-Call") != "b" {
+		t.Errorf("unexpected headers on first call: %v", got[0])
+	}
+	if got[1].Get("X-Shared") != "client" || got[1].Get("X-Call") != "" {
+		t.Errorf("call headers leaked into the next call: %v", got[1])
+	}
+}
+
+func TestCallTimeoutOverridesClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		time.Sleep(100 * time.Millisecond)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Timeout: 20 * time.Millisecond})
+	if _, err := client.Resolve(context.Background(), testParams()); err == nil {
+		t.Fatal("expected client timeout to fire")
+	}
+	if _, err := client.Resolve(context.Background(), testParams(), trp.WithCallTimeout(time.Second)); err != nil {
+		t.Fatalf("expected call timeout to extend the deadline, got %v", err)
+	}
+}
+
+func TestIdempotencyKeyReusedAcrossRetries(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: fastRetry(1)})
+	params := trp.SubmitParams{Tx: core.BytesEnvelope{Content: "beef", ContentType: "hex"}}
+	if _, err := client.Submit(context.Background(), params, trp.WithIdempotencyKey("submit-1")); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "submit-1" || keys[1] != "submit-1" {
+		t.Errorf("expected the key on both attempts, got %q", keys)
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	params := testParams()
+	params.Meta = map[string]string{"origin": "params", "team": "payments"}
+	if _, err := client.Resolve(context.Background(), params, trp.WithLabels(map[string]string{"origin": "checkout", "user": "42"})); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	requests := srv.Requests()
+	var labelled struct {
+		Meta map[string]string `json:"meta"`
+	}
+	json.Unmarshal(requests[0].Params, &labelled)
+	if labelled.Meta["origin"] != "checkout" || labelled.Meta["user"] != "42" || labelled.Meta["team"] != "payments" {
+		t.Errorf("expected labels merged over params meta, got %v", labelled.Meta)
+	}
+	if params.Meta["origin"] != "params" {
+		t.Error("Resolve must not mutate the caller's meta map")
+	}
+	if strings.Contains(string(requests[1].Params), `"meta"`) {
+		t.Errorf("expected meta to be omitted without labels, got %s", requests[1].Params)
+	}
+}
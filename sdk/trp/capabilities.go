@@ -0,0 +1,65 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// ServerCapabilities is what a TRP server advertises about itself.
+type ServerCapabilities struct {
+	Methods     []string `json:"methods"`               // JSON-RPC methods the server implements
+	TirVersions []string `json:"tirVersions,omitempty"` // TIR versions accepted by trp.resolve
+	Encodings   []string `json:"encodings,omitempty"`   // TIR/tx encodings accepted (e.g. "hex", "base64")
+}
+
+// Supports reports whether the server advertises the given method.
+func (s *ServerCapabilities) Supports(method string) bool {
+	return slices.Contains(s.Methods, method)
+}
+
+// SupportsTirVersion reports whether the server advertises the given TIR
+// version.
+func (s *ServerCapabilities) SupportsTirVersion(version string) bool {
+	return slices.Contains(s.TirVersions, version)
+}
+
+// Capabilities asks the server which methods, TIR versions and encodings it
+// supports, via the trp.capabilities method. Servers without it but with
+// OpenRPC service discovery (rpc.discover) yield their method list only.
+// If the server implements neither, *UnsupportedMethodError is returned.
+func (c *Client) Capabilities(ctx context.Context, opts ...CallOption) (*ServerCapabilities, error) {
+	co := newCallOptions(opts)
+	result, err := c.call(ctx, "trp.capabilities", struct{}{}, true, co)
+	if err == nil {
+		var caps ServerCapabilities
+		if err := json.Unmarshal(result, &caps); err != nil {
+			return nil, &DeserializationError{Cause: err, Raw: string(result)}
+		}
+		return &caps, nil
+	}
+	if !IsMethodNotFound(err) {
+		return nil, err
+	}
+
+	result, err = c.call(ctx, "rpc.discover", struct{}{}, true, co)
+	if IsMethodNotFound(err) {
+		return nil, &UnsupportedMethodError{Method: "trp.capabilities", Cause: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Methods []struct {
+			Name string `json:"name"`
+		} `json:"methods"`
+	}
+	if err := json.Unmarshal(result, &doc); err != nil {
+		return nil, &DeserializationError{Cause: err, Raw: string(result)}
+	}
+	caps := &ServerCapabilities{Methods: make([]string, 0, len(doc.Methods))}
+	for _, m := range doc.Methods {
+		caps.Methods = append(caps.Methods, m.Name)
+	}
+	return caps, nil
+}
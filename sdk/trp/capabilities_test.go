@@ -0,0 +1,71 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestCapabilities(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.capabilities": map[string]interface{}{
+			"methods":     []string{"trp.resolve", "trp.submit"},
+			"tirVersions": []string{"v1beta0"},
+			"encodings":   []string{"hex"},
+		},
+	})
+	defer server.Close()
+
+	caps, err := trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities failed: %v", err)
+	}
+	if !caps.Supports("trp.submit") || caps.Supports("trp.checkStatus") {
+		t.Errorf("unexpected methods: %v", caps.Methods)
+	}
+	if !caps.SupportsTirVersion("v1beta0") {
+		t.Errorf("unexpected TIR versions: %v", caps.TirVersions)
+	}
+	if !slices.Equal(caps.Encodings, []string{"hex"}) {
+		t.Errorf("unexpected encodings: %v", caps.Encodings)
+	}
+}
+
+func TestCapabilitiesFallsBackToDiscover(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"rpc.discover": map[string]interface{}{
+			"openrpc": "1.2.6",
+			"methods": []map[string]interface{}{{"name": "trp.resolve"}, {"name": "trp.submit"}},
+		},
+	})
+	defer server.Close()
+
+	caps, err := trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities failed: %v", err)
+	}
+	if !slices.Equal(caps.Methods, []string{"trp.resolve", "trp.submit"}) {
+		t.Errorf("unexpected methods: %v", caps.Methods)
+	}
+}
+
+func TestCapabilitiesUnsupported(t *testing.T) {
+	server := trptest.NewServer(nil)
+	defer server.Close()
+
+	_, err := trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Capabilities(context.Background())
+	var unsupported *trp.UnsupportedMethodError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedMethodError, got %T: %v", err, err)
+	}
+	if unsupported.Method != "trp.capabilities" {
+		t.Errorf("expected method trp.capabilities, got %q", unsupported.Method)
+	}
+	if !trp.IsMethodNotFound(err) {
+		t.Error("expected the method-not-found cause to be preserved")
+	}
+}
@@ -0,0 +1,88 @@
+package trp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// txChunkMethod is the notification carrying one piece of a chunked
+// resolve result.
+const txChunkMethod = "trp.txChunk"
+
+// ndjsonContentType is offered ahead of the codec's own type when
+// ChunkedResults is set.
+const ndjsonContentType = "application/x-ndjson"
+
+// txChunk is the params of a trp.txChunk notification: the next hex-encoded
+// piece of the transaction body.
+type txChunk struct {
+	Tx string `json:"tx"`
+}
+
+// chunkProgress watches a response body as it is read and reports, after
+// every trp.txChunk line, how many transaction bytes have arrived so far.
+type chunkProgress struct {
+	report   func(received int)
+	line     []byte
+	received int
+}
+
+func (p *chunkProgress) Write(b []byte) (int, error) {
+	for _, c := range b {
+		if c != '\n' {
+			p.line = append(p.line, c)
+			continue
+		}
+		var msg jsonRPCResponse
+		if json.Unmarshal(p.line, &msg) == nil && msg.Method == txChunkMethod {
+			var chunk txChunk
+			if json.Unmarshal(msg.Params, &chunk) == nil {
+				p.received += len(chunk.Tx) / 2
+				p.report(p.received)
+			}
+		}
+		p.line = p.line[:0]
+	}
+	return len(b), nil
+}
+
+// assembleTxChunks completes a resolve result from the trp.txChunk
+// notifications that preceded it in body. A result that already carries a
+// tx, or a body without chunks, is returned unchanged.
+func assembleTxChunks(body []byte, result json.RawMessage) (json.RawMessage, error) {
+	var tx strings.Builder
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var msg jsonRPCResponse
+		if err := dec.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, &DeserializationError{Cause: err, Raw: string(body)}
+		}
+		if msg.Method != txChunkMethod {
+			continue
+		}
+		var chunk txChunk
+		if err := json.Unmarshal(msg.Params, &chunk); err != nil {
+			return nil, &DeserializationError{Cause: fmt.Errorf("malformed %s notification: %w", txChunkMethod, err), Raw: string(msg.Params)}
+		}
+		tx.WriteString(chunk.Tx)
+	}
+	if tx.Len() == 0 {
+		return result, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil || fields == nil {
+		return result, nil // decodeEnvelope reports it
+	}
+	var existing string
+	if json.Unmarshal(fields["tx"], &existing) == nil && existing != "" {
+		return result, nil
+	}
+	fields["tx"], _ = json.Marshal(tx.String())
+	return json.Marshal(fields)
+}
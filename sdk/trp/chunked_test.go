@@ -0,0 +1,75 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestChunkedResult(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		id := trptest.RequestID(r)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, chunk := range []string{"84a4", "0081", "8258"} {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","method":"trp.txChunk","params":{"tx":%q}}`+"\n", chunk)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"hash":"abc"}}`+"\n", id)
+	}))
+	defer server.Close()
+
+	var progress []int
+	client := trp.NewClientWithOptions(server.URL, trp.WithChunkedResults())
+	envelope, err := client.Resolve(context.Background(), testParams(), trp.WithChunkProgress(func(received int) {
+		progress = append(progress, received)
+	}))
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Tx != "84a400818258" || envelope.Hash != "abc" {
+		t.Errorf("expected the chunks to be assembled, got %+v", envelope)
+	}
+	if fmt.Sprint(progress) != "[2 4 6]" {
+		t.Errorf("expected progress after every chunk, got %v", progress)
+	}
+	if !strings.HasPrefix(accept, "application/x-ndjson") {
+		t.Errorf("expected NDJSON to be offered, got Accept %q", accept)
+	}
+}
+
+func TestChunkedResultFallsBackToSingleBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithChunkedResults())
+	envelope, err := client.Resolve(context.Background(), testParams(), trp.WithChunkProgress(func(int) {
+		t.Error("unexpected progress for an unchunked result")
+	}))
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Tx != "beef" {
+		t.Errorf("expected the single-body tx, got %q", envelope.Tx)
+	}
+
+	raw, err := client.ResolveRaw(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("ResolveRaw failed: %v", err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(raw, &fields)
+	if fields["tx"] != "beef" {
+		t.Errorf("expected the raw result untouched, got %s", raw)
+	}
+}
@@ -4,39 +4,390 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tx3-lang/go-sdk/sdk/core"
 )
 
 // ClientOptions configures a TRP client.
 type ClientOptions struct {
-	Endpoint string            // Full URL of the TRP JSON-RPC server
-	Headers  map[string]string // Optional custom headers for every request
-	Timeout  time.Duration     // HTTP request timeout (default: 30s)
+	Endpoint string // Full URL of the TRP JSON-RPC server
+
+	// Endpoints, when non-empty, replaces Endpoint with a list of servers.
+	// Idempotent calls fail over to the next endpoint on connection errors
+	// and 5xx responses, walking the list as configured by EndpointOrder.
+	Endpoints     []string
+	EndpointOrder EndpointOrder
+
+	// EndpointConfigs, when non-empty, replaces Endpoints with servers that
+	// each carry their own headers and credentials, sent only to that
+	// server; failover switches credentials along with the endpoint.
+	EndpointConfigs []EndpointConfig
+
+	// Headers are sent with every request. They override the client's own
+	// headers, so e.g. a Content-Type entry suits gateways that want
+	// "application/json-rpc"; credentials options still win for
+	// Authorization.
+	Headers map[string]string
+
+	// UserAgent is sent as the User-Agent header of every request (default:
+	// "tx3-go-sdk/<Version>"). A User-Agent entry in Headers overrides it.
+	UserAgent string
+
+	Timeout time.Duration // Per-attempt HTTP request timeout (default: 30s)
+
+	// RequestTimeout, when set, bounds each call as a whole, across retries
+	// and failover, through a derived context. It combines with Timeout and
+	// any caller deadline; whichever expires first wins.
+	RequestTimeout time.Duration
+
+	Retry   RetryOptions // Automatic retries for idempotent calls (default: disabled)
+	EnvArgs core.EnvMap  // Default env values for every resolve; ResolveParams.Env wins on conflict
+
+	// ArgsEncoder, when set, encodes ResolveParams.Args (or PositionalArgs)
+	// in place of json.Marshal, e.g. to send big integers as JSON strings.
+	// It must produce a JSON object (or array). A failure aborts the call
+	// before any request.
+	ArgsEncoder func(args interface{}) (json.RawMessage, error)
+
+	// EnvProvider, when set, is called before every resolve to produce fresh
+	// env values (e.g. the current slot). They are merged over EnvArgs and
+	// under ResolveParams.Env. An error aborts the call before any request.
+	EnvProvider func(ctx context.Context) (map[string]interface{}, error)
+
+	// JSONRPCVersion is sent as the "jsonrpc" member of every request, and
+	// responses must echo it (default: "2.0").
+	JSONRPCVersion string
+
+	// ResolveMethod overrides the JSON-RPC method used by Resolve and
+	// ResolveBatch, for gateways that namespace methods (default: "trp.resolve").
+	ResolveMethod string
+
+	// FallbackMethod, when set, is tried by Resolve (and ResolveRaw,
+	// ResolveDetailed and Validate) if the server answers ResolveMethod
+	// with a method-not-found error, e.g. while a server migration renames
+	// it. Any other error is returned as is. The primary method is tried
+	// first on every call. ResolveBatch does not fall back.
+	FallbackMethod string
+
+	// BatchSharedEnv, when true, makes ResolveBatch send the env entries
+	// common to all items only once, using the trp.resolveBatch extension.
+	// Support is detected through Capabilities on first use; servers without
+	// it get a plain JSON-RPC batch.
+	BatchSharedEnv bool
+
+	// MaxBatchSize, when positive, splits ResolveBatch calls with more items
+	// into sub-batches of at most that many, sent in turn, so a deadline
+	// cuts the batch short instead of failing it wholesale.
+	MaxBatchSize int
+
+	// SendTirHash, when true, fills in ResolveParams.Tir.Hash with
+	// TirEnvelope.ComputeHash when the caller left it empty, so caching
+	// servers can recognise a TIR they have compiled before.
+	SendTirHash bool
+
+	// LenientEncoding, when true, marks resolved envelopes Lenient: a tx
+	// the server labels base64 but that only decodes as hex is accepted, by
+	// TxEnvelope.Bytes and by VerifyHash, instead of failing.
+	LenientEncoding bool
+
+	// StrictDecoding, when true, fails a resolve whose result has fields
+	// TxEnvelope does not model, with *DeserializationError naming the
+	// field. Off by default so new server fields do not break clients; it
+	// is meant for CI runs that should notice protocol drift.
+	StrictDecoding bool
+
+	// CompatibleTirVersions, when non-empty, lists the TIR versions the
+	// server is known to accept. Resolving a TIR of any other version fails
+	// client-side with UnsupportedTirError, without contacting the server.
+	CompatibleTirVersions []string
+
+	// IDGenerator, when set, produces the JSON-RPC request ids (default:
+	// random UUIDs).
+	IDGenerator func() string
+
+	// ResponseInterceptor, when set, is invoked with the raw HTTP status and
+	// body of every response (successful or not) before it is parsed. It
+	// receives a copy of the body and cannot affect the call's outcome.
+	ResponseInterceptor func(status int, body []byte)
+
+	// RequestRecorder, when set, is invoked with the encoded JSON-RPC body
+	// of every HTTP request, before compression, e.g. to compare the wire
+	// format against golden files. It receives a copy of the body. Set
+	// IDGenerator too for reproducible ids. StreamRequests is ignored while
+	// it is set, as there would be no body to record.
+	RequestRecorder func(body []byte)
+
+	// NotificationHandler, when set, receives the notifications (messages
+	// without an id, e.g. progress reports) a server sends ahead of the
+	// response to a call; without it they are ignored. It is called on the
+	// goroutine reading the response and must not block.
+	NotificationHandler func(method string, params json.RawMessage)
+
+	// ChunkedResults lets the server stream a resolved transaction as
+	// NDJSON: trp.txChunk notifications, each with params {"tx": "<hex>"},
+	// followed by the response, whose result may then omit tx. The chunks
+	// are joined into TxEnvelope.Tx, and WithChunkProgress reports them as
+	// they arrive. Servers that answer with a single body are read as
+	// before. JSONCodec only.
+	ChunkedResults bool
+
+	// ErrorFormatter, when set, builds the Error() text of GenericRpcError
+	// from the JSON-RPC error code, message and decoded data (nil if there
+	// is none), e.g. to present localized messages. Typed errors such as
+	// MissingTxArgError keep their own wording.
+	ErrorFormatter func(code int, message string, data interface{}) string
+
+	// Middleware wraps every JSON-RPC call, the first entry outermost. Each
+	// sees the call once, around all of its retries, and may modify it or
+	// short-circuit it. The JSON-RPC array sent by ResolveBatch bypasses
+	// middleware.
+	Middleware []Middleware
+
+	// Logger, when set, receives a debug line per HTTP round trip (endpoint,
+	// method, request id, status, elapsed time). Headers and credentials are
+	// never logged.
+	Logger Logger
+
+	// Tracer, when set, wraps every JSON-RPC call in a trace span. See
+	// package trpotel for an OpenTelemetry implementation.
+	Tracer Tracer
+
+	// Metrics, when set, is notified of the duration and outcome of every
+	// Resolve, or of every call if it is a MethodObserver. See package
+	// trpprom for a Prometheus implementation.
+	Metrics MetricsObserver
+
+	// ValidateEnvelope, when true, makes Resolve and ResolveBatch reject a
+	// result whose Tx is empty or whose Hash is not 64 hex digits, with
+	// InvalidEnvelopeError. It is opt-in for now.
+	ValidateEnvelope bool
+
+	// VerifyHash, when true, makes Resolve and ResolveBatch recompute the
+	// transaction id (Blake2b-256 of the CBOR tx body) from the returned Tx
+	// and fail with TxHashMismatchError if it differs from Hash.
+	VerifyHash bool
+
+	// RateLimiter, when set, is waited on before every HTTP request,
+	// including retries and failover attempts.
+	RateLimiter RateLimiter
+
+	// MaxConcurrentRequests, when positive, caps the HTTP requests this
+	// client has in flight at once. Further requests block until a slot
+	// frees up or their context is done, failing then with
+	// ConcurrencyLimitError. Slots are held per attempt, not across retry
+	// backoff. Zero means unlimited.
+	MaxConcurrentRequests int
+
+	// MaxResponseBytes caps the size of a response body, after
+	// decompression; larger responses fail with ResponseTooLargeError.
+	// Zero means the default of 8 MiB; a negative value disables the cap.
+	MaxResponseBytes int64
+
+	// MaxRequestBytes, when positive, caps the size of an encoded request
+	// body, before compression. Larger requests fail with
+	// RequestTooLargeError without being sent, rather than with a 413 from
+	// a gateway. StreamRequests is ignored while it is set.
+	MaxRequestBytes int64
+
+	// Codec sets the wire encoding of requests and responses (default:
+	// JSONCodec). CBORCodec suits servers that accept application/cbor.
+	Codec Codec
+
+	// Compression, when true, gzip-compresses request bodies. Responses are
+	// always requested with Accept-Encoding: gzip and decompressed
+	// transparently; uncompressed responses are accepted as-is.
+	Compression bool
+
+	// StreamRequests, when true, encodes resolve request bodies while they
+	// are being sent instead of marshalling them up front, so a large TIR is
+	// never held in memory twice. Streamed bodies are sent chunked, without a
+	// Content-Length, which some proxies and servers reject; hence the
+	// default is to buffer. A Signer always forces buffering.
+	StreamRequests bool
+
+	// BasicAuth, when set, is sent as "Authorization: Basic ...", overriding
+	// any Authorization entry in Headers. BearerToken and TokenProvider take
+	// precedence over it.
+	BasicAuth *BasicAuth
+
+	// BearerToken, when set, is sent as "Authorization: Bearer <token>",
+	// overriding any Authorization entry in Headers.
+	BearerToken string
+
+	// TokenProvider, when set, is invoked before every request to obtain a
+	// fresh bearer token. It takes precedence over BearerToken and Headers.
+	TokenProvider func(ctx context.Context) (string, error)
+
+	// Signer, when set, is invoked with every fully built HTTP request and
+	// its exact body bytes (compressed, if Compression is on) just before it
+	// is sent, e.g. to add HMAC signature headers. It runs again for each
+	// retry. An error aborts the call with SignerError. Because it needs the
+	// whole body, a Signer makes Resolve buffer its request even when
+	// StreamRequests is set.
+	Signer func(req *http.Request, body []byte) error
+
+	// CircuitBreaker, when set, guards each endpoint with a circuit breaker:
+	// after repeated transport failures or 5xx responses, calls to that
+	// endpoint fail fast with CircuitOpenError until a cooldown has passed.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// Clock, when set, replaces the real clock for retry backoff, circuit
+	// breaker cooldowns and reported durations; intended for tests.
+	Clock Clock
+
+	// FollowRedirects, when true, follows 307/308 redirects (up to
+	// MaxRedirects, default 10). Credentials are only forwarded to the same
+	// host. By default redirects are not followed and surface as HttpError.
+	FollowRedirects bool
+	MaxRedirects    int
+
+	// DialTimeout, when set, bounds establishing each TCP connection,
+	// independently of Timeout, so failover moves on quickly from an
+	// unreachable endpoint (default: 30s, as in http.DefaultTransport).
+	DialTimeout time.Duration
+
+	// TLSHandshakeTimeout, when set, bounds the TLS handshake of each new
+	// https connection (default: 10s, as in http.DefaultTransport). It
+	// starts once DialTimeout's TCP connect has succeeded, so a new
+	// connection may take up to their sum; both fall under Timeout.
+	TLSHandshakeTimeout time.Duration
+
+	// UnixSocket, when set, is the path of a Unix domain socket every
+	// connection is dialled to, e.g. for a TRP sidecar. Endpoint still
+	// supplies the URL sent over it, such as "http://localhost/rpc". It
+	// cannot be combined with ProxyURL.
+	UnixSocket string
+
+	// ProxyURL, when set, routes requests through the given proxy (http,
+	// https or socks5 scheme) instead of the environment's proxy settings.
+	// An invalid URL makes every call fail; see ClientOptions.Validate.
+	ProxyURL string
+
+	// MaxIdleConns, MaxIdleConnsPerHost and IdleConnTimeout size the pool of
+	// keep-alive connections kept open between requests. Zero keeps the
+	// http.DefaultTransport settings: 100 idle connections in total, 2 per
+	// host (http.DefaultMaxIdleConnsPerHost) and a 90s idle timeout.
+	// Services making many concurrent calls to one endpoint should raise
+	// MaxIdleConnsPerHost towards their concurrency to avoid reconnecting.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+
+	// DisableKeepAlives, when true, closes each connection after its
+	// request, for short-lived processes where pooled connections only go
+	// stale. The pool settings above then have no effect.
+	DisableKeepAlives bool
+
+	// MinTLSVersion, when set, is the oldest TLS version accepted from the
+	// server (e.g. tls.VersionTLS12). Go's own default applies otherwise.
+	MinTLSVersion uint16
+
+	// PinnedCertSHA256, when non-empty, restricts the servers trusted to
+	// those presenting a certificate whose SHA-256 fingerprint (hex, of the
+	// DER encoding) is listed; the leaf or any certificate of its chain may
+	// be pinned. The usual CA verification still applies. A mismatch fails
+	// the call with *CertificatePinError.
+	PinnedCertSHA256 []string
+
+	// ForceHTTP2 multiplexes calls over HTTP/2 for plain http:// endpoints
+	// too, which then must support h2c: cleartext offers no negotiation to
+	// fall back on. https:// endpoints negotiate HTTP/2 with or without it,
+	// falling back to HTTP/1.1 for servers that do not offer h2.
+	ForceHTTP2 bool
+
+	// HTTPClient, when non-nil, is used as-is for every request. Timeout,
+	// DialTimeout, UnixSocket, ProxyURL, the connection pool, TLS, HTTP/2
+	// and redirect settings are ignored in that case; the supplied client's
+	// own settings apply.
+	HTTPClient *http.Client
 }
 
 // Client is a low-level TRP JSON-RPC client.
+//
+// A Client is safe for concurrent use by multiple goroutines and is meant to
+// be shared process-wide. Its configuration is fixed at construction: the
+// maps and slices in ClientOptions are copied, so later changes by the
+// caller do not affect it, and per-call options never touch shared state.
 type Client struct {
 	options    ClientOptions
 	httpClient *http.Client
+	timeout    time.Duration              // Per-attempt timeout; zero leaves it to httpClient
+	breakers   map[string]*circuitBreaker // Per endpoint; nil when CircuitBreaker is unset
+	configErr  error                      // From ClientOptions.Validate; fails every call
+	closed     atomic.Bool
+	inflight   *inflight     // Calls Shutdown cancels and waits for
+	slots      chan struct{} // Request slots; nil when MaxConcurrentRequests is unset
+
+	resolveBatchProbe extensionProbe // Whether the server supports trp.resolveBatch
 }
 
-// NewClient creates a new TRP client with the given options.
+// NewClient creates a new TRP client with the given options. It is
+// equivalent to NewClientWithOptions with the same settings applied.
 func NewClient(options ClientOptions) *Client {
+	return newClient(options)
+}
+
+// Dial creates a TRP client for endpoint with default settings. It is
+// shorthand for NewClient(ClientOptions{Endpoint: endpoint}).
+func Dial(endpoint string) *Client {
+	return newClient(ClientOptions{Endpoint: endpoint})
+}
+
+// newClient is the shared constructor behind NewClient and
+// NewClientWithOptions.
+func newClient(options ClientOptions) *Client {
+	options.Headers = maps.Clone(options.Headers)
+	options.EnvArgs = maps.Clone(options.EnvArgs)
+	options.Endpoints = slices.Clone(options.Endpoints)
+	if len(options.EndpointConfigs) > 0 {
+		options.EndpointConfigs = slices.Clone(options.EndpointConfigs)
+		options.Endpoints = make([]string, len(options.EndpointConfigs))
+		for i := range options.EndpointConfigs {
+			options.EndpointConfigs[i].Headers = maps.Clone(options.EndpointConfigs[i].Headers)
+			options.Endpoints[i] = options.EndpointConfigs[i].URL
+		}
+	}
+	options.CompatibleTirVersions = slices.Clone(options.CompatibleTirVersions)
+	options.Middleware = slices.Clone(options.Middleware)
+	options.PinnedCertSHA256 = slices.Clone(options.PinnedCertSHA256)
+	c := &Client{options: options, configErr: options.Validate(), inflight: newInflight()}
+	c.breakers = newCircuitBreakers(c.endpoints(), options.CircuitBreaker, c.clock())
+	if options.MaxConcurrentRequests > 0 {
+		c.slots = make(chan struct{}, options.MaxConcurrentRequests)
+	}
+	if options.HTTPClient != nil {
+		c.httpClient = options.HTTPClient
+		return c
+	}
 	timeout := options.Timeout
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
-	return &Client{
-		options: options,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+	// The timeout is enforced per attempt through the request context rather
+	// than http.Client.Timeout, so WithCallTimeout can extend it.
+	c.httpClient = &http.Client{CheckRedirect: c.checkRedirect}
+	transport, err := newTransport(options)
+	switch {
+	case err != nil:
+		if c.configErr == nil {
+			c.configErr = err
+		}
+	case transport != nil:
+		c.httpClient.Transport = transport
 	}
+	c.timeout = timeout
+	return c
 }
 
 // jsonRPCRequest is a JSON-RPC 2.0 request envelope.
@@ -53,6 +404,10 @@ type jsonRPCResponse struct {
 	ID      string          `json:"id"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *rpcError       `json:"error,omitempty"`
+
+	// Set on notifications from the server, which carry no id.
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
 }
 
 type rpcError struct {
@@ -61,38 +416,280 @@ type rpcError struct {
 	Data    json.RawMessage `json:"data,omitempty"`
 }
 
-// call executes a JSON-RPC method and returns the raw result.
-func (c *Client) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
-	reqBody := jsonRPCRequest{
-		JSONRPC: "2.0",
-		ID:      uuid.New().String(),
+// outgoing is a marshalled JSON-RPC payload ready to be posted.
+type outgoing struct {
+	method     string // JSON-RPC method, for diagnostics
+	id         string // Request id (or batch label), for diagnostics
+	body       []byte
+	payload    interface{} // When set instead of body, encoded afresh into each attempt
+	idempotent bool        // Safe to retry and fail over
+	call       callOptions
+}
+
+// call executes a JSON-RPC method through the client's middleware and
+// returns the raw result. Idempotent calls are retried according to the
+// client's RetryOptions.
+func (c *Client) call(ctx context.Context, method string, params interface{}, idempotent bool, co callOptions) (json.RawMessage, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if len(c.options.Middleware) == 0 {
+		return c.invoke(ctx, method, params, idempotent, co)
+	}
+	h := c.withMiddleware(func(ctx context.Context, call *Call) (json.RawMessage, error) {
+		return c.invoke(ctx, call.Method, call.Params, idempotent, co)
+	})
+	return h(ctx, &Call{Method: method, Params: params})
+}
+
+// invoke sends a single JSON-RPC call, with retries, past the middleware.
+func (c *Client) invoke(ctx context.Context, method string, params interface{}, idempotent bool, co callOptions) (json.RawMessage, error) {
+	req := c.newRequest(method, params)
+	out := outgoing{method: method, id: req.ID, idempotent: idempotent, call: co}
+	if co.streamBody && c.canStream() {
+		out.payload = req
+	} else {
+		bodyBytes, err := c.codec().Marshal(req)
+		if err != nil {
+			return nil, &NetworkError{Cause: fmt.Errorf("failed to marshal request: %w", err)}
+		}
+		out.body = bodyBytes
+		if err := c.checkRequestSize(bodyBytes); err != nil {
+			return nil, err
+		}
+	}
+	if out.call.meta != nil {
+		out.call.meta.RequestID = req.ID
+	}
+
+	ctx, leave, err := c.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+	ctx, finish := c.startCall(ctx, out)
+	var result json.RawMessage
+	err = c.retry(ctx, idempotent, func() error {
+		respBody, err := c.deliver(ctx, out)
+		if err != nil {
+			return err
+		}
+		result, err = c.decodeResponse(respBody, out.id)
+		if err == nil && out.call.chunked {
+			result, err = assembleTxChunks(respBody, result)
+		}
+		return err
+	})
+	finish(result, err)
+	return result, err
+}
+
+// checkRequestSize enforces MaxRequestBytes on an encoded request body.
+func (c *Client) checkRequestSize(body []byte) error {
+	if limit := c.options.MaxRequestBytes; limit > 0 && int64(len(body)) > limit {
+		return &RequestTooLargeError{Size: int64(len(body)), Limit: limit}
+	}
+	return nil
+}
+
+// defaultMaxResponseBytes is generous for real transactions while bounding
+// memory use against a misbehaving server.
+const defaultMaxResponseBytes = 8 << 20
+
+// maxResponseBytes returns the effective response size cap, or a
+// non-positive value if there is none.
+func (c *Client) maxResponseBytes() int64 {
+	if c.options.MaxResponseBytes == 0 {
+		return defaultMaxResponseBytes
+	}
+	return c.options.MaxResponseBytes
+}
+
+// withRequestTimeout derives the context bounding a whole call.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.options.RequestTimeout > 0 {
+		return context.WithTimeout(ctx, c.options.RequestTimeout)
+	}
+	return ctx, func() {}
+}
+
+// newRequest builds a JSON-RPC request envelope with a fresh id.
+func (c *Client) newRequest(method string, params interface{}) jsonRPCRequest {
+	return jsonRPCRequest{
+		JSONRPC: c.jsonRPCVersion(),
+		ID:      c.nextID(),
 		Method:  method,
 		Params:  params,
 	}
+}
+
+// nextID returns the id for the next JSON-RPC request.
+func (c *Client) nextID() string {
+	if c.options.IDGenerator != nil {
+		return c.options.IDGenerator()
+	}
+	return uuid.New().String()
+}
 
-	bodyBytes, err := json.Marshal(reqBody)
+// retry runs fn, repeating it on retryable failures when the call is
+// idempotent. It never waits past the context deadline.
+func (c *Client) retry(ctx context.Context, idempotent bool, fn func() error) error {
+	retries := 0
+	if idempotent {
+		retries = c.options.Retry.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !c.options.Retry.shouldRetry(err, attempt) || ctx.Err() != nil {
+			return err
+		}
+		delay := c.options.Retry.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-c.clock().After(delay):
+		}
+	}
+}
+
+// post performs a single HTTP round trip to endpoint carrying an
+// already-marshalled JSON-RPC body and returns the raw response body of a 200
+// response. It fails fast with CircuitOpenError while the endpoint's circuit
+// breaker is open.
+func (c *Client) post(ctx context.Context, endpoint string, out outgoing) ([]byte, error) {
+	breaker := c.breakers[endpoint]
+	if err := breaker.allow(endpoint); err != nil {
+		return nil, err
+	}
+	respBody, err := c.send(ctx, endpoint, out)
+	breaker.record(ctx, err)
+	return respBody, err
+}
+
+// send is post without the circuit breaker.
+func (c *Client) send(ctx context.Context, endpoint string, out outgoing) ([]byte, error) {
+	if err := c.waitRateLimit(ctx); err != nil {
+		return nil, err
+	}
+	release, err := c.acquireSlot(ctx)
 	if err != nil {
-		return nil, &NetworkError{Cause: fmt.Errorf("failed to marshal request: %w", err)}
+		return nil, err
+	}
+	defer release()
+
+	body := out.body
+	if c.options.RequestRecorder != nil {
+		c.options.RequestRecorder(bytes.Clone(body))
+	}
+	if c.options.Compression && out.payload == nil {
+		compressed, err := gzipBytes(body)
+		if err != nil {
+			return nil, &NetworkError{Cause: fmt.Errorf("failed to compress request: %w", err)}
+		}
+		body = compressed
+	}
+
+	timeout := c.timeout
+	if out.call.timeout > 0 {
+		timeout = out.call.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.options.Endpoint, bytes.NewReader(bodyBytes))
+	var reqBody io.Reader
+	if out.payload == nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, reqBody)
 	if err != nil {
 		return nil, &NetworkError{Cause: err}
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", c.codec().ContentType())
+	req.Header.Set("Accept", c.codec().ContentType())
+	if out.call.chunked {
+		req.Header.Set("Accept", ndjsonContentType+", "+c.codec().ContentType())
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", c.userAgent())
+	if c.options.Compression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 	for k, v := range c.options.Headers {
 		req.Header.Set(k, v)
 	}
+	if err := c.applyAuth(ctx, req.Header); err != nil {
+		return nil, err
+	}
+	c.applyEndpointConfig(endpoint, req.Header)
+	if out.call.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", out.call.idempotencyKey)
+	}
+	for k, v := range out.call.headers {
+		req.Header.Set(k, v)
+	}
+	if c.options.Signer != nil {
+		if err := c.options.Signer(req, body); err != nil {
+			return nil, &SignerError{Cause: err}
+		}
+	}
 
+	if out.call.meta != nil {
+		out.call.meta.Attempts++
+		out.call.meta.Endpoint = endpoint
+	}
+	var stream *streamedBody
+	if out.payload != nil {
+		// Attached last so no early return can strand the encoding goroutine.
+		stream = c.streamBody(out.payload)
+		req.Body = stream
+		req.GetBody = func() (io.ReadCloser, error) { return c.streamBody(out.payload), nil }
+	}
+	remote := &remoteAddr{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), remote.trace()))
+	start := c.clock().Now()
 	resp, err := c.httpClient.Do(req)
+	if out.call.meta != nil {
+		out.call.meta.RemoteAddr = remote.get()
+	}
 	if err != nil {
-		return nil, &NetworkError{Cause: err}
+		if stream != nil {
+			if encErr := stream.failure(); encErr != nil {
+				return nil, encErr
+			}
+		}
+		c.debugf("trp: %s id=%s endpoint=%s remote=%s failed after %s: %v", out.method, out.id, endpoint, remote.get(), c.since(start), err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &NetworkError{Cause: fmt.Errorf("%s aborted: %w", out.method, ctxErr), RemoteAddr: remote.get()}
+		}
+		return nil, &NetworkError{Cause: err, RemoteAddr: remote.get()}
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	var progress io.Writer
+	if out.call.chunked && out.call.chunkProgress != nil {
+		progress = &chunkProgress{report: out.call.chunkProgress}
+	}
+	respBody, err := readBody(resp, c.maxResponseBytes(), progress)
 	if err != nil {
-		return nil, &NetworkError{Cause: fmt.Errorf("failed to read response body: %w", err)}
+		var tooLarge *ResponseTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		return nil, &NetworkError{Cause: fmt.Errorf("failed to read response body: %w", err), RemoteAddr: remote.get()}
+	}
+	c.debugf("trp: %s id=%s endpoint=%s status=%d elapsed=%s", out.method, out.id, endpoint, resp.StatusCode, c.since(start))
+
+	if c.options.ResponseInterceptor != nil {
+		c.options.ResponseInterceptor(resp.StatusCode, bytes.Clone(respBody))
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -100,48 +697,84 @@ func (c *Client) call(ctx context.Context, method string, params interface{}) (j
 			Status:     resp.StatusCode,
 			StatusText: resp.Status,
 			Body:       string(respBody),
+			Header:     resp.Header,
+			RemoteAddr: remote.get(),
 		}
 	}
 
-	var rpcResp jsonRPCResponse
-	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
-		return nil, &DeserializationError{Cause: err, Raw: string(respBody)}
+	return respBody, nil
+}
+
+// decodeResponse parses a single JSON-RPC response to the request with the
+// given id and returns its result.
+func (c *Client) decodeResponse(respBody []byte, id string) (json.RawMessage, error) {
+	rpcResp, err := c.finalResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkResponse(rpcResp, id); err != nil {
+		return nil, err
 	}
+	return rpcResp.result(c.options.ErrorFormatter)
+}
 
-	if rpcResp.Error != nil {
-		return nil, classifyRpcError(rpcResp.Error)
+// jsonRPCVersion returns the protocol version sent and expected back.
+func (c *Client) jsonRPCVersion() string {
+	if c.options.JSONRPCVersion != "" {
+		return c.options.JSONRPCVersion
 	}
+	return "2.0"
+}
 
-	if rpcResp.Result == nil {
-		return nil, &MalformedResponseError{Detail: "response has no result"}
+// checkResponse verifies that r answers the request with the given id under
+// the configured protocol version. Error responses may carry a null id, as
+// servers that cannot parse a request have no id to echo.
+func (c *Client) checkResponse(r *jsonRPCResponse, id string) error {
+	if version := c.jsonRPCVersion(); r.JSONRPC != version {
+		return &ResponseMismatchError{Field: "jsonrpc", Sent: version, Received: r.JSONRPC}
 	}
+	if r.ID != id && !(r.ID == "" && r.Error != nil) {
+		return &ResponseMismatchError{Field: "id", Sent: id, Received: r.ID}
+	}
+	return nil
+}
 
-	return rpcResp.Result, nil
+// result extracts the result of a decoded response, mapping a JSON-RPC error
+// object to a typed TRP error. format is the client's ErrorFormatter.
+func (r *jsonRPCResponse) result(format func(code int, message string, data interface{}) string) (json.RawMessage, error) {
+	if r.Error != nil {
+		return nil, classifyRpcError(r.Error, format)
+	}
+	if r.Result == nil {
+		return nil, &MalformedResponseError{Detail: "response has no result"}
+	}
+	return r.Result, nil
 }
 
-// classifyRpcError maps a JSON-RPC error to a typed TRP error.
-func classifyRpcError(e *rpcError) error {
+// classifyRpcError maps a JSON-RPC error to a typed TRP error. Errors that
+// remain generic are rendered with format, if set.
+func classifyRpcError(e *rpcError, format func(code int, message string, data interface{}) string) error {
 	// Try to parse structured diagnostic data
 	if e.Data != nil {
 		var diag map[string]interface{}
 		if json.Unmarshal(e.Data, &diag) == nil {
 			if kind, ok := diag["kind"].(string); ok {
 				switch kind {
-				case "UnsupportedTir":
+				case KindUnsupportedTir:
 					return &UnsupportedTirError{
 						Expected: stringFromMap(diag, "expected"),
 						Provided: stringFromMap(diag, "provided"),
 					}
-				case "MissingTxArg":
+				case KindMissingTxArg:
 					return &MissingTxArgError{
 						Key:     stringFromMap(diag, "key"),
 						ArgType: stringFromMap(diag, "argType"),
 					}
-				case "InputNotResolved":
+				case KindInputNotResolved:
 					return &InputNotResolvedError{
 						Name: stringFromMap(diag, "name"),
 					}
-				case "TxScriptFailure":
+				case KindTxScriptFailure:
 					var logs []string
 					if logsRaw, ok := diag["logs"]; ok {
 						if logsArr, ok := logsRaw.([]interface{}); ok {
@@ -153,11 +786,11 @@ func classifyRpcError(e *rpcError) error {
 						}
 					}
 					return &TxScriptFailureError{Logs: logs}
-				case "InvalidTirEnvelope":
+				case KindInvalidTirEnvelope:
 					return &InvalidTirEnvelopeError{}
-				case "InvalidTirBytes":
+				case KindInvalidTirBytes:
 					return &InvalidTirBytesError{}
-				case "UnsupportedEra":
+				case KindUnsupportedEra:
 					return &UnsupportedEraError{Era: stringFromMap(diag, "era")}
 				}
 			}
@@ -167,6 +800,7 @@ func classifyRpcError(e *rpcError) error {
 		Code:    e.Code,
 		Message: e.Message,
 		Data:    e.Data,
+		format:  format,
 	}
 }
 
@@ -179,22 +813,245 @@ func stringFromMap(m map[string]interface{}, key string) string {
 	return ""
 }
 
-// Resolve invokes the trp.resolve JSON-RPC method.
-func (c *Client) Resolve(ctx context.Context, params ResolveParams) (*TxEnvelope, error) {
-	result, err := c.call(ctx, "trp.resolve", params)
+// Resolve invokes the trp.resolve JSON-RPC method (or the configured
+// ResolveMethod).
+//
+// The TIR envelope is validated client-side first; an envelope whose content
+// does not decode under its declared encoding yields *InvalidTirError without
+// contacting the server. opts override client settings for this call only.
+func (c *Client) Resolve(ctx context.Context, params ResolveParams, opts ...CallOption) (*TxEnvelope, error) {
+	envelope, _, err := c.ResolveDetailed(ctx, params, opts...)
+	return envelope, err
+}
+
+// ResolveMetadata describes how a resolve was carried out. It is returned
+// by ResolveDetailed on success and on failure alike.
+type ResolveMetadata struct {
+	RequestID string        // JSON-RPC id sent to the server; empty if no request was built
+	Duration  time.Duration // Wall-clock time of the whole call, including retries and backoff
+	Attempts  int           // HTTP requests sent, counting retries and failover
+	Endpoint  string        // Endpoint of the last HTTP request sent; empty if none was
+
+	// RemoteAddr is the address the last HTTP request was connected to,
+	// or last tried to connect to if it never was: the backend node behind
+	// Endpoint, or the proxy when one is configured.
+	RemoteAddr string
+}
+
+// ResolveDetailed is like Resolve but also returns metadata about the call,
+// such as the request id to quote when reporting an issue to the server
+// operator, its duration and how many attempts it took.
+func (c *Client) ResolveDetailed(ctx context.Context, params ResolveParams, opts ...CallOption) (envelope *TxEnvelope, meta ResolveMetadata, err error) {
+	start := c.clock().Now()
+	defer func() { meta.Duration = c.since(start) }()
+	opts = append(opts[:len(opts):len(opts)], func(o *callOptions) { o.meta = &meta })
+	envelope, err = c.resolve(ctx, params, opts)
+	return envelope, meta, err
+}
+
+func (c *Client) resolve(ctx context.Context, params ResolveParams, opts []CallOption) (envelope *TxEnvelope, err error) {
+	defer c.observeResolve(c.clock().Now(), &err)
+	co := newCallOptions(opts)
+	result, err := c.resolveCall(ctx, params, co)
+	if err != nil || co.validateOnly {
+		return nil, err
+	}
+	return c.resolved(result)
+}
+
+// ResolveRaw is like Resolve but returns the unparsed JSON-RPC result, for
+// callers that need result fields TxEnvelope does not model. The request,
+// auth, retries and error handling are the same; ValidateEnvelope and
+// VerifyHash do not apply.
+func (c *Client) ResolveRaw(ctx context.Context, params ResolveParams, opts ...CallOption) (result json.RawMessage, err error) {
+	defer c.observeResolve(c.clock().Now(), &err)
+	return c.resolveCall(ctx, params, newCallOptions(opts))
+}
+
+// resolveCall prepares params and performs the resolve call.
+func (c *Client) resolveCall(ctx context.Context, params ResolveParams, co callOptions) (json.RawMessage, error) {
+	env, err := c.defaultEnv(ctx)
 	if err != nil {
 		return nil, err
 	}
+	params, err = c.prepareResolve(params, env)
+	if err != nil {
+		return nil, err
+	}
+	if co.validateOnly {
+		params.Options = params.Options.withValidateOnly()
+	}
+	params = co.withLabels(params)
+	// TIR bytecode can run to megabytes; with StreamRequests, encoding it
+	// straight onto the wire avoids holding a second copy as the body.
+	co.streamBody = true
+	co.chunked = c.options.ChunkedResults && c.codec() == JSONCodec
+	result, err := c.call(ctx, c.resolveMethod(), params, true, co)
+	if fallback, ok := c.fallbackMethod(err); ok {
+		return c.call(ctx, fallback, params, true, co)
+	}
+	return result, err
+}
+
+// observeResolve reports a resolve that began at start and ended with *err
+// to the configured MetricsObserver, unless it is a MethodObserver and
+// observes the call itself.
+func (c *Client) observeResolve(start time.Time, err *error) {
+	if c.options.Metrics != nil && c.methodObserver() == nil {
+		c.options.Metrics.ObserveResolve(c.since(start), *err)
+	}
+}
+
+// Validate asks the server whether it would resolve params, without
+// producing a transaction. It returns nil if the TIR and args are accepted,
+// or the server's validation error otherwise.
+func (c *Client) Validate(ctx context.Context, params ResolveParams, opts ...CallOption) error {
+	opts = append(opts[:len(opts):len(opts)], WithValidateOnly())
+	_, err := c.resolve(ctx, params, opts)
+	return err
+}
+
+// resolveMethod returns the JSON-RPC method name used for resolution.
+func (c *Client) resolveMethod() string {
+	if c.options.ResolveMethod != "" {
+		return c.options.ResolveMethod
+	}
+	return "trp.resolve"
+}
+
+// fallbackMethod returns the FallbackMethod to retry a resolve with, if
+// the primary method failed with err because the server does not know it.
+func (c *Client) fallbackMethod(err error) (string, bool) {
+	fallback := c.options.FallbackMethod
+	if fallback == "" || fallback == c.resolveMethod() || !IsMethodNotFound(err) {
+		return "", false
+	}
+	c.debugf("trp: %s not found, falling back to %s", c.resolveMethod(), fallback)
+	return fallback, true
+}
+
+// defaultEnv returns the client's default env for a resolve: the static
+// EnvArgs overlaid with the EnvProvider's values.
+func (c *Client) defaultEnv(ctx context.Context) (map[string]interface{}, error) {
+	if c.options.EnvProvider == nil {
+		return c.options.EnvArgs, nil
+	}
+	provided, err := c.options.EnvProvider(ctx)
+	if err != nil {
+		return nil, &EnvProviderError{Cause: err}
+	}
+	if len(c.options.EnvArgs) == 0 {
+		return provided, nil
+	}
+	env := maps.Clone(c.options.EnvArgs)
+	maps.Copy(env, provided)
+	return env, nil
+}
+
+// prepareResolve validates the TIR envelope, folds the default env under
+// the request's own Env and applies SendTirHash and ArgsEncoder. The
+// caller's maps are not mutated.
+func (c *Client) prepareResolve(params ResolveParams, defaults map[string]interface{}) (ResolveParams, error) {
+	if err := params.Tir.Validate(); err != nil {
+		return params, &InvalidTirError{Cause: err}
+	}
+	// A lenient envelope may only have validated through the hex fallback;
+	// send it labelled as what it is.
+	params.Tir = params.Tir.Canonical()
+	if allowed := c.options.CompatibleTirVersions; len(allowed) > 0 && !slices.Contains(allowed, params.Tir.Version) {
+		return params, &UnsupportedTirError{Expected: strings.Join(allowed, " or "), Provided: params.Tir.Version}
+	}
+	if len(defaults) > 0 {
+		env := make(map[string]interface{}, len(defaults)+len(params.Env))
+		for k, v := range defaults {
+			env[k] = v
+		}
+		for k, v := range params.Env {
+			env[k] = v
+		}
+		params.Env = env
+	}
+	if c.options.SendTirHash && params.Tir.Hash == "" {
+		// The content was validated above, so hashing cannot fail.
+		params.Tir.Hash, _ = params.Tir.ComputeHash()
+	}
+	if c.options.ArgsEncoder != nil && params.Args != nil {
+		args, err := c.encodeArgs(params.Args)
+		if err != nil {
+			return params, &NetworkError{Cause: &encodeError{cause: err}}
+		}
+		params.Args = args
+	}
+	if c.options.ArgsEncoder != nil && params.PositionalArgs != nil {
+		args, err := c.encodePositionalArgs(params.PositionalArgs)
+		if err != nil {
+			return params, &NetworkError{Cause: &encodeError{cause: err}}
+		}
+		params.PositionalArgs = args
+	}
+	return params, nil
+}
+
+// encodeArgs runs ArgsEncoder and splits its output into pre-encoded
+// values, so the request still carries a map and the codec copies each
+// value verbatim.
+func (c *Client) encodeArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := c.options.ArgsEncoder(args)
+	if err != nil {
+		return nil, fmt.Errorf("ArgsEncoder: %w", err)
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || values == nil {
+		return nil, fmt.Errorf("ArgsEncoder must produce a JSON object, got %.64q", raw)
+	}
+	encoded := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		encoded[k] = v
+	}
+	return encoded, nil
+}
+
+// encodePositionalArgs is encodeArgs for PositionalArgs.
+func (c *Client) encodePositionalArgs(args core.ArgList) (core.ArgList, error) {
+	raw, err := c.options.ArgsEncoder(args)
+	if err != nil {
+		return nil, fmt.Errorf("ArgsEncoder: %w", err)
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil || values == nil {
+		return nil, fmt.Errorf("ArgsEncoder must produce a JSON array for positional args, got %.64q", raw)
+	}
+	encoded := make(core.ArgList, len(values))
+	for i, v := range values {
+		encoded[i] = v
+	}
+	return encoded, nil
+}
+
+// decodeEnvelope parses a trp.resolve result. With strict set, fields
+// TxEnvelope does not model are an error.
+func decodeEnvelope(result json.RawMessage, strict bool) (*TxEnvelope, error) {
 	var envelope TxEnvelope
-	if err := json.Unmarshal(result, &envelope); err != nil {
-		return nil, &DeserializationError{Cause: err, Raw: string(result)}
+	if !strict {
+		if err := json.Unmarshal(result, &envelope); err != nil {
+			return nil, &DeserializationError{Cause: err, Raw: string(result)}
+		}
+		return &envelope, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&envelope); err != nil {
+		return nil, &DeserializationError{Cause: fmt.Errorf("strict decoding of resolve result: %w", err), Raw: string(result)}
 	}
 	return &envelope, nil
 }
 
-// Submit invokes the trp.submit JSON-RPC method.
-func (c *Client) Submit(ctx context.Context, params SubmitParams) (*SubmitResponse, error) {
-	result, err := c.call(ctx, "trp.submit", params)
+// Submit invokes the trp.submit JSON-RPC method and returns the hash the
+// server accepted. A signed transaction has a fixed hash, so resubmitting it
+// is safe: submissions are retried and failed over like any other call.
+// Rejections are reported as typed TRP errors carrying the server's reason.
+func (c *Client) Submit(ctx context.Context, params SubmitParams, opts ...CallOption) (*SubmitResponse, error) {
+	result, err := c.call(ctx, "trp.submit", params, true, newCallOptions(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -206,9 +1063,9 @@ func (c *Client) Submit(ctx context.Context, params SubmitParams) (*SubmitRespon
 }
 
 // CheckStatus invokes the trp.checkStatus JSON-RPC method.
-func (c *Client) CheckStatus(ctx context.Context, hashes []string) (*CheckStatusResponse, error) {
+func (c *Client) CheckStatus(ctx context.Context, hashes []string, opts ...CallOption) (*CheckStatusResponse, error) {
 	params := CheckStatusParams{Hashes: hashes}
-	result, err := c.call(ctx, "trp.checkStatus", params)
+	result, err := c.call(ctx, "trp.checkStatus", params, true, newCallOptions(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -218,3 +1075,11 @@ func (c *Client) CheckStatus(ctx context.Context, hashes []string) (*CheckStatus
 	}
 	return &resp, nil
 }
+
+// Ping invokes the trp.health JSON-RPC method and returns nil if the server
+// answers with a result. It uses the same headers, timeout, retries, and auth
+// as Resolve, making it suitable for readiness probes.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.call(ctx, "trp.health", struct{}{}, true, callOptions{})
+	return err
+}
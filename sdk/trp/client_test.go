@@ -2,21 +2,42 @@ package trp_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/tx3-lang/go-sdk/sdk/core"
 	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
 )
 
+var testTir = core.TirEnvelope{
+	Content:  "aabbcc",
+	Encoding: "hex",
+	Version:  "v1beta0",
+}
+
+// testParams returns minimal resolve params carrying a valid TIR envelope.
+func testParams() trp.ResolveParams {
+	return trp.ResolveParams{Tir: testTir}
+}
+
 func TestResolveRequestShape(t *testing.T) {
 	var receivedMethod string
 	var receivedParams json.RawMessage
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
 		receivedMethod = string(req["method"])
@@ -24,7 +45,7 @@ func TestResolveRequestShape(t *testing.T) {
 
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"jsonrpc": "2.0",
-			"id":      "1",
+			"id":      id,
 			"result": map[string]interface{}{
 				"hash": "abc123",
 				"tx":   "deadbeef",
@@ -62,22 +83,238 @@ func TestResolveRequestShape(t *testing.T) {
 	}
 }
 
-func TestSubmitRequestShape(t *testing.T) {
+func TestResolveMethodOverride(t *testing.T) {
 	var receivedMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&req)
+		json.Unmarshal(req["method"], &receivedMethod)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, ResolveMethod: "v2.trp.resolve"})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if receivedMethod != "v2.trp.resolve" {
+		t.Errorf("expected method 'v2.trp.resolve', got %q", receivedMethod)
+	}
+}
+
+func TestFallbackMethod(t *testing.T) {
+	envelope := map[string]interface{}{"hash": "abc", "tx": "beef"}
+	renamed := trptest.NewMethodServer(map[string]interface{}{"trp.resolve": envelope})
+	defer renamed.Close()
+
+	client := trp.NewClientWithOptions(renamed.URL, trp.WithResolveMethod("v2.trp.resolve"), trp.WithFallbackMethod("trp.resolve"))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("expected the fallback method to resolve, got %v", err)
+	}
+
+	failing := trptest.NewMethodServer(map[string]interface{}{
+		"v2.trp.resolve": &trptest.Error{Code: trptest.CodeServerError, Message: "boom"},
+		"trp.resolve":    envelope,
+	})
+	defer failing.Close()
 
+	client = trp.NewClientWithOptions(failing.URL, trp.WithResolveMethod("v2.trp.resolve"), trp.WithFallbackMethod("trp.resolve"))
+	var rpcErr *trp.GenericRpcError
+	if _, err := client.Resolve(context.Background(), testParams()); !errors.As(err, &rpcErr) || rpcErr.Message != "boom" {
+		t.Errorf("expected errors other than method-not-found to be returned, got %v", err)
+	}
+}
+
+func TestCustomIDGenerator(t *testing.T) {
+	var receivedID string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req map[string]json.RawMessage
 		json.NewDecoder(r.Body).Decode(&req)
-		receivedMethod = string(req["method"])
+		json.Unmarshal(req["id"], &receivedID)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      receivedID,
+			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
+		})
+	}))
+	defer server.Close()
+
+	counter := 0
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		IDGenerator: func() string {
+			counter++
+			return fmt.Sprintf("req-%d", counter)
+		},
+	})
+	client.Resolve(context.Background(), testParams())
+	client.Resolve(context.Background(), testParams())
+	if receivedID != "req-2" {
+		t.Errorf("expected id 'req-2', got %q", receivedID)
+	}
+}
+
+func TestResolveDetailedReportsRequestID(t *testing.T) {
+	var receivedIDs []string
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		receivedIDs = append(receivedIDs, req.ID)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if fail {
+			resp["error"] = map[string]interface{}{"code": -32000, "message": "boom"}
+		} else {
+			resp["result"] = map[string]interface{}{"hash": "abc", "tx": "beef"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	envelope, meta, err := client.ResolveDetailed(context.Background(), testParams())
+	if err != nil || envelope.Hash != "abc" {
+		t.Fatalf("ResolveDetailed failed: %v", err)
+	}
+	if meta.RequestID == "" || meta.RequestID != receivedIDs[0] {
+		t.Errorf("expected request id %q, got %q", receivedIDs[0], meta.RequestID)
+	}
+
+	fail = true
+	_, meta, err = client.ResolveDetailed(context.Background(), testParams())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if meta.RequestID != receivedIDs[1] {
+		t.Errorf("expected request id %q on error, got %q", receivedIDs[1], meta.RequestID)
+	}
+}
 
+func TestResolveDetailedReportsAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Retry:    trp.RetryOptions{MaxRetries: 3, InitialBackoff: time.Millisecond},
+	})
+	_, meta, err := client.ResolveDetailed(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("ResolveDetailed failed: %v", err)
+	}
+	if meta.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", meta.Attempts)
+	}
+	if meta.Endpoint != server.URL {
+		t.Errorf("expected endpoint %q, got %q", server.URL, meta.Endpoint)
+	}
+	if meta.Duration <= 0 {
+		t.Errorf("expected a positive duration, got %s", meta.Duration)
+	}
+
+	client = trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	calls.Store(0)
+	_, meta, err = client.ResolveDetailed(context.Background(), testParams())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if meta.Attempts != 1 || meta.Endpoint != server.URL || meta.Duration <= 0 {
+		t.Errorf("expected metadata on error, got %+v", meta)
+	}
+}
+
+func TestResolveRaw(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+		if fail {
+			resp["error"] = map[string]interface{}{"code": -32000, "message": "boom"}
+		} else {
+			resp["result"] = map[string]interface{}{"hash": "abc", "tx": "beef", "fee": 170000}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	raw, err := client.ResolveRaw(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("ResolveRaw failed: %v", err)
+	}
+	var result struct {
+		Hash string `json:"hash"`
+		Fee  uint64 `json:"fee"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("raw result is not JSON: %v", err)
+	}
+	if result.Hash != "abc" || result.Fee != 170000 {
+		t.Errorf("unexpected raw result: %s", raw)
+	}
+
+	fail = true
+	_, err = client.ResolveRaw(context.Background(), testParams())
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected GenericRpcError, got %T: %v", err, err)
+	}
+}
+
+func TestErrorFormatter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"jsonrpc": "2.0",
-			"id":      "1",
-			"result":  map[string]interface{}{"hash": "abc123"},
+			"id":      id,
+			"error":   map[string]interface{}{"code": -32000, "message": "quota exceeded", "data": map[string]interface{}{"limit": 10}},
 		})
 	}))
 	defer server.Close()
 
+	client := trp.NewClientWithOptions(server.URL, trp.WithErrorFormatter(func(code int, message string, data interface{}) string {
+		limit := data.(map[string]interface{})["limit"]
+		return fmt.Sprintf("Too many requests (limit %v). Please try again later.", limit)
+	}))
+	_, err := client.Resolve(context.Background(), testParams())
+	if want := "Too many requests (limit 10). Please try again later."; err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32000 {
+		t.Errorf("expected the GenericRpcError fields to be kept, got %T: %v", err, err)
+	}
+
+	_, err = trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Resolve(context.Background(), testParams())
+	if want := "TRP RPC error -32000: quota exceeded"; err == nil || err.Error() != want {
+		t.Errorf("expected the default format %q, got %v", want, err)
+	}
+}
+
+func TestSubmitRequestShape(t *testing.T) {
+	var receivedMethod string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&req)
+		receivedMethod = string(req["method"])
+
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc123"})
+	}))
+	defer server.Close()
+
 	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
 	_, err := client.Submit(context.Background(), trp.SubmitParams{
 		Tx: core.NewHexEnvelope("deadbeef"),
@@ -99,9 +336,10 @@ func TestSubmitRequestShape(t *testing.T) {
 
 func TestCheckStatusRequestShape(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"jsonrpc": "2.0",
-			"id":      "1",
+			"id":      id,
 			"result": map[string]interface{}{
 				"statuses": map[string]interface{}{
 					"abc123": map[string]interface{}{
@@ -131,12 +369,13 @@ func TestCheckStatusRequestShape(t *testing.T) {
 
 func TestHttpErrorResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 	}))
 	defer server.Close()
 
 	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
-	_, err := client.Resolve(context.Background(), trp.ResolveParams{})
+	_, err := client.Resolve(context.Background(), testParams())
 	if err == nil {
 		t.Fatal("expected error for HTTP 500")
 	}
@@ -147,13 +386,20 @@ func TestHttpErrorResponse(t *testing.T) {
 	if httpErr.Status != 500 {
 		t.Errorf("expected status 500, got %d", httpErr.Status)
 	}
+	if got := httpErr.Header.Get("Retry-After"); got != "7" {
+		t.Errorf("expected Retry-After header '7', got %q", got)
+	}
+	if got := httpErr.Error(); got != "TRP HTTP error 500 Internal Server Error: internal server error" {
+		t.Errorf("unexpected error string %q", got)
+	}
 }
 
 func TestJsonRpcErrorResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"jsonrpc": "2.0",
-			"id":      "1",
+			"id":      id,
 			"error": map[string]interface{}{
 				"code":    -32600,
 				"message": "Invalid Request",
@@ -163,7 +409,7 @@ func TestJsonRpcErrorResponse(t *testing.T) {
 	defer server.Close()
 
 	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
-	_, err := client.Resolve(context.Background(), trp.ResolveParams{})
+	_, err := client.Resolve(context.Background(), testParams())
 	if err == nil {
 		t.Fatal("expected error for JSON-RPC error")
 	}
@@ -174,11 +420,43 @@ func TestJsonRpcErrorResponse(t *testing.T) {
 	if rpcErr.Code != -32600 {
 		t.Errorf("expected code -32600, got %d", rpcErr.Code)
 	}
+	if !strings.Contains(rpcErr.Error(), "-32600") {
+		t.Errorf("expected error string to include code, got %q", rpcErr.Error())
+	}
+}
+
+func TestGenericRpcErrorOmitsZeroCode(t *testing.T) {
+	err := &trp.GenericRpcError{Message: "boom"}
+	if got := err.Error(); got != "TRP RPC error: boom" {
+		t.Errorf("unexpected error string %q", got)
+	}
+}
+
+func TestGenericRpcErrorUnmarshalData(t *testing.T) {
+	err := &trp.GenericRpcError{
+		Code:    -32050,
+		Message: "inputs missing",
+		Data:    json.RawMessage(`{"missing":["abc#0","def#1"]}`),
+	}
+	var detail struct {
+		Missing []string `json:"missing"`
+	}
+	if e := err.UnmarshalData(&detail); e != nil {
+		t.Fatalf("UnmarshalData failed: %v", e)
+	}
+	if len(detail.Missing) != 2 || detail.Missing[1] != "def#1" {
+		t.Errorf("unexpected detail %+v", detail)
+	}
+
+	empty := &trp.GenericRpcError{Code: -32000, Message: "boom"}
+	if e := empty.UnmarshalData(&detail); e == nil {
+		t.Error("expected error when no data is present")
+	}
 }
 
 func TestNetworkError(t *testing.T) {
 	client := trp.NewClient(trp.ClientOptions{Endpoint: "http://localhost:1"})
-	_, err := client.Resolve(context.Background(), trp.ResolveParams{})
+	_, err := client.Resolve(context.Background(), testParams())
 	if err == nil {
 		t.Fatal("expected network error")
 	}
@@ -191,10 +469,213 @@ func TestNetworkError(t *testing.T) {
 func TestCustomHeadersInjected(t *testing.T) {
 	var receivedAuth string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		receivedAuth = r.Header.Get("Authorization")
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"Authorization": "Bearer token123"},
+	})
+	client.Resolve(context.Background(), testParams())
+	if receivedAuth != "Bearer token123" {
+		t.Errorf("expected Authorization header 'Bearer token123', got %q", receivedAuth)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		received = r.Header.Get("User-Agent")
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name    string
+		options trp.ClientOptions
+		want    string
+	}{
+		{"default", trp.ClientOptions{}, "tx3-go-sdk/" + trp.Version},
+		{"option", trp.ClientOptions{UserAgent: "my-app/1.2"}, "my-app/1.2"},
+		{"header wins", trp.ClientOptions{UserAgent: "my-app/1.2", Headers: map[string]string{"User-Agent": "custom"}}, "custom"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.options.Endpoint = server.URL
+			if _, err := trp.NewClient(tc.options).Resolve(context.Background(), testParams()); err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if received != tc.want {
+				t.Errorf("expected User-Agent %q, got %q", tc.want, received)
+			}
+		})
+	}
+}
+
+func TestResolveContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, err := client.Resolve(ctx, testParams())
+	if err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+	var netErr *trp.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected NetworkError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error to wrap context.Canceled, got %v", err)
+	}
+}
+
+type countingTransport struct {
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.calls++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestCustomHTTPClientUsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:   server.URL,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if transport.calls != 1 {
+		t.Errorf("expected custom transport to be used once, got %d", transport.calls)
+	}
+}
+
+func TestBearerTokenOverridesHeader(t *testing.T) {
+	var receivedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
 		receivedAuth = r.Header.Get("Authorization")
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:    server.URL,
+		Headers:     map[string]string{"Authorization": "Bearer stale"},
+		BearerToken: "static",
+	})
+	client.Resolve(context.Background(), testParams())
+	if receivedAuth != "Bearer static" {
+		t.Errorf("expected 'Bearer static', got %q", receivedAuth)
+	}
+
+	calls := 0
+	client = trp.NewClient(trp.ClientOptions{
+		Endpoint:    server.URL,
+		BearerToken: "static",
+		TokenProvider: func(ctx context.Context) (string, error) {
+			calls++
+			return fmt.Sprintf("fresh-%d", calls), nil
+		},
+	})
+	client.Resolve(context.Background(), testParams())
+	client.Resolve(context.Background(), testParams())
+	if receivedAuth != "Bearer fresh-2" {
+		t.Errorf("expected provider token per request, got %q", receivedAuth)
+	}
+}
+
+func TestBasicAuthOverridesHeader(t *testing.T) {
+	var user, pass string
+	var ok bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		user, pass, ok = r.BasicAuth()
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithHeader("Authorization", "Bearer stale"),
+		trp.WithHeader("X-Extra", "kept"),
+		trp.WithBasicAuth("alice", "s3cret"),
+		trp.WithLogger(logger),
+	)
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if !ok || user != "alice" || pass != "s3cret" {
+		t.Errorf("expected basic auth alice:s3cret, got %q:%q (ok=%v)", user, pass, ok)
+	}
+	for _, line := range logger.lines {
+		if strings.Contains(line, "s3cret") {
+			t.Errorf("credentials leaked into log line %q", line)
+		}
+	}
+}
+
+func TestTokenProviderErrorAbortsRequest(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		TokenProvider: func(ctx context.Context) (string, error) {
+			return "", errors.New("expired")
+		},
+	})
+	_, err := client.Resolve(context.Background(), testParams())
+	var tpErr *trp.TokenProviderError
+	if !errors.As(err, &tpErr) {
+		t.Fatalf("expected TokenProviderError, got %T: %v", err, err)
+	}
+	if hit {
+		t.Error("request should not reach the server when the token provider fails")
+	}
+}
+
+func TestSignerSignsBody(t *testing.T) {
+	sign := func(body []byte) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get("X-Signature"), sign(body); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+		var req struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(body, &req)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"jsonrpc": "2.0",
-			"id":      "1",
+			"id":      req.ID,
 			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
 		})
 	}))
@@ -202,10 +683,447 @@ func TestCustomHeadersInjected(t *testing.T) {
 
 	client := trp.NewClient(trp.ClientOptions{
 		Endpoint: server.URL,
-		Headers:  map[string]string{"Authorization": "Bearer token123"},
+		Signer: func(req *http.Request, body []byte) error {
+			req.Header.Set("X-Signature", sign(body))
+			return nil
+		},
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+}
+
+func TestSignerErrorAbortsRequest(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Retry:    trp.RetryOptions{MaxRetries: 2, InitialBackoff: time.Millisecond},
+		Signer: func(req *http.Request, body []byte) error {
+			return errors.New("no key")
+		},
+	})
+	_, err := client.Resolve(context.Background(), testParams())
+	var signerErr *trp.SignerError
+	if !errors.As(err, &signerErr) {
+		t.Fatalf("expected SignerError, got %T: %v", err, err)
+	}
+	if hit {
+		t.Error("request should not reach the server when the signer fails")
+	}
+}
+
+func TestResolveRejectsInvalidTir(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, err := client.Resolve(context.Background(), trp.ResolveParams{
+		Tir: core.TirEnvelope{Content: "aabbcc", Encoding: "base64", Version: "v1beta0"},
 	})
+	var tirErr *trp.InvalidTirError
+	if !errors.As(err, &tirErr) {
+		t.Fatalf("expected InvalidTirError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "not valid base64") {
+		t.Errorf("expected encoding detail in error, got %q", err.Error())
+	}
+	if hit {
+		t.Error("invalid TIR should be rejected before any HTTP call")
+	}
+}
+
+func TestCompatibleTirVersions(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		hits.Add(1)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithCompatibleTirVersions("v1beta0", "v1beta1"))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed for a compatible version: %v", err)
+	}
+
+	params := testParams()
+	params.Tir.Version = "v1alpha9"
+	_, err := client.Resolve(context.Background(), params)
+	var unsupported *trp.UnsupportedTirError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedTirError, got %T: %v", err, err)
+	}
+	if want := "unsupported TIR: expected v1beta0 or v1beta1, got v1alpha9"; err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected the incompatible TIR not to reach the server; got %d requests", hits.Load())
+	}
+}
+
+func TestPing(t *testing.T) {
+	var receivedMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&req)
+		json.Unmarshal(req["method"], &receivedMethod)
+		trptest.WriteResult(w, id, map[string]interface{}{"status": "ok"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if receivedMethod != "trp.health" {
+		t.Errorf("expected method 'trp.health', got %q", receivedMethod)
+	}
+}
+
+func TestPingUnreachable(t *testing.T) {
+	client := trp.NewClient(trp.ClientOptions{Endpoint: "http://localhost:1"})
+	err := client.Ping(context.Background())
+	var netErr *trp.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected NetworkError, got %T: %v", err, err)
+	}
+}
+
+func TestResponseInterceptorSeesRawResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var seenStatus int
+	var seenBody string
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		ResponseInterceptor: func(status int, body []byte) {
+			seenStatus = status
+			seenBody = string(body)
+			for i := range body {
+				body[i] = 'x'
+			}
+		},
+	})
+	_, err := client.Resolve(context.Background(), testParams())
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HttpError, got %T: %v", err, err)
+	}
+	if seenStatus != http.StatusBadGateway || !strings.Contains(seenBody, "upstream down") {
+		t.Errorf("interceptor saw status %d body %q", seenStatus, seenBody)
+	}
+	if !strings.Contains(httpErr.Body, "upstream down") {
+		t.Errorf("interceptor must not alter the returned error, got body %q", httpErr.Body)
+	}
+}
+
+func TestRequestRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer srv.Close()
+
+	var recorded [][]byte
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:        srv.URL,
+		Compression:     true,
+		StreamRequests:  true,
+		IDGenerator:     func() string { return "golden" },
+		RequestRecorder: func(body []byte) { recorded = append(recorded, body) },
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(recorded) != 1 {
+		t.Fatalf("expected one recorded request, got %d", len(recorded))
+	}
+	want := `{"jsonrpc":"2.0","id":"golden","method":"trp.resolve","params":{"tir":{"content":"` + testTir.Content + `"`
+	if !strings.HasPrefix(string(recorded[0]), want) {
+		t.Errorf("expected the uncompressed request body, got %s", recorded[0])
+	}
+}
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLoggerRecordsRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:    server.URL,
+		BearerToken: "top-secret",
+		Logger:      logger,
+		IDGenerator: func() string { return "req-1" },
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(logger.lines) != 1 {
+		t.Fatalf("expected one log line, got %v", logger.lines)
+	}
+	line := logger.lines[0]
+	for _, want := range []string{"trp.resolve", "req-1", server.URL, "status=200"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %q missing %q", line, want)
+		}
+	}
+	if strings.Contains(line, "top-secret") {
+		t.Errorf("log line leaked credentials: %q", line)
+	}
+}
+
+type recordingObserver struct {
+	calls int
+	errs  int
+}
+
+func (o *recordingObserver) ObserveResolve(duration time.Duration, err error) {
+	o.calls++
+	if err != nil {
+		o.errs++
+	}
+}
+
+type methodObserver struct {
+	recordingObserver
+	methods []string
+}
+
+func (o *methodObserver) ObserveCall(method string, duration time.Duration, err error) {
+	o.methods = append(o.methods, method)
+}
+
+func TestMethodObserverSeesEveryMethod(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.resolve": map[string]interface{}{"hash": "abc", "tx": "beef"},
+		"trp.submit":  map[string]interface{}{"hash": "abc"},
+	})
+	defer server.Close()
+
+	observer := &methodObserver{}
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Metrics: observer})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if _, err := client.Submit(context.Background(), trp.SubmitParams{}); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if len(observer.methods) != 2 || observer.methods[0] != "trp.resolve" || observer.methods[1] != "trp.submit" {
+		t.Errorf("expected one observation per method, got %v", observer.methods)
+	}
+	if observer.calls != 0 {
+		t.Errorf("expected ObserveResolve to be unused, got %d calls", observer.calls)
+	}
+}
+
+func TestMetricsObserverNotified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Metrics: observer})
+	client.Resolve(context.Background(), testParams())
 	client.Resolve(context.Background(), trp.ResolveParams{})
-	if receivedAuth != "Bearer token123" {
-		t.Errorf("expected Authorization header 'Bearer token123', got %q", receivedAuth)
+	if observer.calls != 2 || observer.errs != 1 {
+		t.Errorf("expected 2 observations with 1 error, got %d/%d", observer.calls, observer.errs)
+	}
+}
+
+func TestRequestTimeoutBoundsWholeCall(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:       server.URL,
+		Timeout:        30 * time.Millisecond,
+		RequestTimeout: 100 * time.Millisecond,
+		Retry:          fastRetry(10),
+	})
+
+	start := time.Now()
+	_, err := client.Resolve(context.Background(), testParams())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("RequestTimeout did not bound the call: %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got < 2 || got > 5 {
+		t.Errorf("expected per-attempt Timeout to allow a few retries, got %d attempts", got)
+	}
+}
+
+func TestValidateOnly(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req.Params)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": map[string]interface{}{"valid": true}}
+		if req.Params["args"] == nil {
+			resp = map[string]interface{}{"jsonrpc": "2.0", "id": id, "error": map[string]interface{}{
+				"code": -32000, "message": "missing args",
+				"data": map[string]interface{}{"kind": "MissingTxArg", "key": "quantity", "argType": "Int"},
+			}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	params := testParams()
+	params.Args = map[string]interface{}{"quantity": 1}
+	if err := client.Validate(context.Background(), params); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	options, _ := received[0]["options"].(map[string]interface{})
+	if options["validateOnly"] != true {
+		t.Errorf("expected options.validateOnly to be sent, got %v", received[0]["options"])
+	}
+	if params.Options != nil {
+		t.Error("Validate must not mutate the caller's params")
+	}
+
+	envelope, err := client.Resolve(context.Background(), params, trp.WithValidateOnly())
+	if err != nil || envelope != nil {
+		t.Errorf("expected nil envelope and error for a valid dry run, got %v, %v", envelope, err)
+	}
+
+	err = client.Validate(context.Background(), testParams())
+	var missing *trp.MissingTxArgError
+	if !errors.As(err, &missing) || missing.Key != "quantity" {
+		t.Errorf("expected MissingTxArgError, got %T: %v", err, err)
+	}
+
+	client.Resolve(context.Background(), params)
+	if _, ok := received[len(received)-1]["options"]; ok {
+		t.Error("expected no options on a plain resolve")
+	}
+}
+
+func TestResponseMismatchDetected(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"id":      {"jsonrpc": "2.0", "id": "someone-else", "result": map[string]interface{}{"hash": "abc", "tx": "beef"}},
+		"jsonrpc": {"jsonrpc": "1.0", "id": nil, "result": map[string]interface{}{"hash": "abc", "tx": "beef"}},
+	}
+	for field, resp := range cases {
+		t.Run(field, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if resp["id"] == nil {
+					resp["id"] = trptest.RequestID(r)
+				}
+				json.NewEncoder(w).Encode(resp)
+			}))
+			defer server.Close()
+
+			client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+			_, err := client.Resolve(context.Background(), testParams())
+			var mismatch *trp.ResponseMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("expected ResponseMismatchError, got %T: %v", err, err)
+			}
+			if mismatch.Field != field {
+				t.Errorf("expected mismatch on %q, got %q", field, mismatch.Field)
+			}
+		})
+	}
+}
+
+func TestErrorResponseWithNullIDAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      nil,
+			"error":   map[string]interface{}{"code": -32700, "message": "Parse error"},
+		})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, err := client.Resolve(context.Background(), testParams())
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32700 {
+		t.Fatalf("expected the server's parse error, got %T: %v", err, err)
+	}
+}
+
+func TestCustomJSONRPCVersion(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req struct {
+			JSONRPC string `json:"jsonrpc"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		received = req.JSONRPC
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": req.JSONRPC,
+			"id":      id,
+			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
+		})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithJSONRPCVersion("2.1"))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if received != "2.1" {
+		t.Errorf("expected jsonrpc '2.1' to be sent, got %q", received)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": strings.Repeat("ab", 1024)})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, MaxResponseBytes: 1024})
+	_, err := client.Resolve(context.Background(), testParams())
+	var tooLarge *trp.ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 {
+		t.Fatalf("expected ResponseTooLargeError, got %T: %v", err, err)
+	}
+
+	client = trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("expected default cap to admit the response, got %v", err)
 	}
 }
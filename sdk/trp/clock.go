@@ -0,0 +1,33 @@
+package trp
+
+import "time"
+
+// Clock is the client's source of time, so tests can substitute a fake one
+// and drive backoff, circuit breaker cooldowns and reported durations
+// deterministically.
+//
+// Timeouts (Timeout, RequestTimeout, WithCallTimeout) are enforced through
+// context deadlines and always follow the real clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time // Used for every wait, so it can be cut short by a context
+}
+
+// realClock is the default Clock, backed by package time.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clock returns the configured Clock, defaulting to the real one.
+func (c *Client) clock() Clock {
+	if c.options.Clock != nil {
+		return c.options.Clock
+	}
+	return realClock{}
+}
+
+// since is time.Since on the client's clock.
+func (c *Client) since(t time.Time) time.Duration {
+	return c.clock().Now().Sub(t)
+}
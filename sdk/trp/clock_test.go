@@ -0,0 +1,142 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+// fakeClock only moves when told to. After fires immediately, advancing the
+// clock by the requested delay, so backoff never actually waits.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delays = append(f.delays, d)
+	f.now = f.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestClockDrivesRetryBackoff(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Clock:    clock,
+		Retry:    trp.RetryOptions{MaxRetries: 3, InitialBackoff: time.Hour, MaxBackoff: 4 * time.Hour},
+	})
+	start := time.Now()
+	_, meta, err := client.ResolveDetailed(context.Background(), testParams())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("backoff waited on the real clock")
+	}
+	if hits.Load() != 4 || len(clock.delays) != 3 {
+		t.Fatalf("expected 4 attempts and 3 backoff waits, got %d and %d", hits.Load(), len(clock.delays))
+	}
+	for i, d := range clock.delays {
+		nominal := time.Hour << i
+		if d < nominal/2 || d > nominal {
+			t.Errorf("backoff %d: expected within [%s, %s], got %s", i, nominal/2, nominal, d)
+		}
+	}
+	var total time.Duration
+	for _, d := range clock.delays {
+		total += d
+	}
+	if meta.Duration != total {
+		t.Errorf("expected Duration %s measured on the fake clock, got %s", total, meta.Duration)
+	}
+}
+
+func TestRetryDeadlineFollowsRealClock(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// The fake clock is far from real time; only the real time left before
+	// the deadline may decide whether a backoff fits.
+	clock := newFakeClock()
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Clock:    clock,
+		Retry:    trp.RetryOptions{MaxRetries: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Resolve(ctx, testParams()); err == nil {
+		t.Fatal("expected error")
+	}
+	if hits.Load() != 1 || len(clock.delays) != 0 {
+		t.Errorf("expected to give up before a backoff longer than the deadline, got %d attempts and %d waits", hits.Load(), len(clock.delays))
+	}
+}
+
+func TestClockDrivesCircuitBreakerCooldown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithClock(clock),
+		trp.WithCircuitBreaker(trp.CircuitBreakerOptions{FailureThreshold: 1, Cooldown: time.Minute}))
+	ctx := context.Background()
+
+	client.Resolve(ctx, testParams())
+	var openErr *trp.CircuitOpenError
+	if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &openErr) {
+		t.Fatalf("expected CircuitOpenError, got %T: %v", err, err)
+	}
+	if openErr.RetryAfter != time.Minute {
+		t.Errorf("expected RetryAfter 1m on a frozen clock, got %s", openErr.RetryAfter)
+	}
+
+	clock.Advance(time.Minute)
+	var httpErr *trp.HttpError
+	if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &httpErr) {
+		t.Fatalf("expected a probe after the cooldown, got %T: %v", err, err)
+	}
+}
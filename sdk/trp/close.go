@@ -0,0 +1,25 @@
+package trp
+
+// Close releases idle connections held by the underlying HTTP transport and
+// marks the client closed. Calls made after Close fail with
+// *ClientClosedError; calls already in flight are not interrupted (see
+// Shutdown). Close is idempotent.
+func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// checkOpen reports an error if the client has been closed or was built
+// from invalid options.
+func (c *Client) checkOpen() error {
+	if c.configErr != nil {
+		return c.configErr
+	}
+	if c.closed.Load() {
+		return &ClientClosedError{}
+	}
+	return nil
+}
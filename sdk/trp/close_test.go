@@ -0,0 +1,98 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+type idleClosingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *idleClosingTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestCloseReleasesConnectionsAndRejectsCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	transport := &idleClosingTransport{RoundTripper: http.DefaultTransport}
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:   server.URL,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("second Close failed: %v", err)
+	}
+	if transport.closed != 1 {
+		t.Errorf("expected idle connections closed once, got %d", transport.closed)
+	}
+
+	var closedErr *trp.ClientClosedError
+	if _, err := client.Resolve(context.Background(), testParams()); !errors.As(err, &closedErr) {
+		t.Errorf("expected ClientClosedError from Resolve, got %T: %v", err, err)
+	}
+	if _, _, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{testParams()}); !errors.As(err, &closedErr) {
+		t.Errorf("expected ClientClosedError from ResolveBatch, got %T: %v", err, err)
+	}
+}
+
+func TestShutdownCancelsInFlightCalls(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		once.Do(func() { close(started) })
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.Resolve(context.Background(), testParams())
+		errc <- err
+	}()
+	<-started
+
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected in-flight call to be cancelled, got %T: %v", err, err)
+		}
+	default:
+		t.Fatal("Shutdown returned before the in-flight call")
+	}
+
+	var closedErr *trp.ClientClosedError
+	if _, err := client.Resolve(context.Background(), testParams()); !errors.As(err, &closedErr) {
+		t.Errorf("expected ClientClosedError after Shutdown, got %T: %v", err, err)
+	}
+}
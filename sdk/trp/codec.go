@@ -0,0 +1,120 @@
+package trp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Codec controls the wire encoding of JSON-RPC messages: how requests are
+// marshalled, the Content-Type and Accept headers, and how responses are
+// decoded. The request and response structures are the same whatever the
+// encoding.
+type Codec interface {
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSONCodec encodes JSON-RPC as JSON. It is the default.
+	JSONCodec Codec = jsonCodec{}
+
+	// CBORCodec encodes JSON-RPC as CBOR (RFC 8949), for servers that accept
+	// application/cbor. It is smaller on the wire and cheaper to parse.
+	CBORCodec Codec = cborCodec{}
+)
+
+// codec returns the configured codec, defaulting to JSONCodec.
+func (c *Client) codec() Codec {
+	if c.options.Codec != nil {
+		return c.options.Codec
+	}
+	return JSONCodec
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return "application/json" }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// cborCodec transcodes through JSON so that the wire types keep their JSON
+// tags and custom (un)marshallers, and json.RawMessage fields such as the
+// result still hold JSON for the rest of the client.
+type cborCodec struct{}
+
+var cborDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+}.DecMode()
+
+func (cborCodec) ContentType() string { return "application/cbor" }
+
+func (cborCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	tree, err = numbersToCBOR(tree)
+	if err != nil {
+		return nil, err
+	}
+	return cbor.Marshal(tree)
+}
+
+func (cborCodec) Unmarshal(data []byte, v interface{}) error {
+	var tree interface{}
+	if err := cborDecMode.Unmarshal(data, &tree); err != nil {
+		return err
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return fmt.Errorf("cbor value has no JSON equivalent: %w", err)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numbersToCBOR replaces the json.Numbers in a decoded JSON tree with
+// integers where they are integral, so they encode as CBOR integers
+// (arbitrarily large ones as bignums) rather than floats.
+func numbersToCBOR(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		if i, ok := new(big.Int).SetString(v.String(), 10); ok {
+			return i, nil
+		}
+		return v.Float64()
+	case map[string]interface{}:
+		for k, e := range v {
+			e, err := numbersToCBOR(e)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = e
+		}
+	case []interface{}:
+		for i, e := range v {
+			e, err := numbersToCBOR(e)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = e
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,101 @@
+package trp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestCBORCodec(t *testing.T) {
+	var req struct {
+		JSONRPC string                 `cbor:"jsonrpc"`
+		Method  string                 `cbor:"method"`
+		Params  map[string]interface{} `cbor:"params"`
+		ID      string                 `cbor:"id"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/cbor" {
+			t.Errorf("expected Content-Type application/cbor, got %q", ct)
+		}
+		if accept := r.Header.Get("Accept"); accept != "application/cbor" {
+			t.Errorf("expected Accept application/cbor, got %q", accept)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := cbor.Unmarshal(body, &req); err != nil {
+			t.Errorf("request body is not CBOR: %v", err)
+			return
+		}
+		resp, _ := cbor.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
+		})
+		w.Header().Set("Content-Type", "application/cbor")
+		w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithCodec(trp.CBORCodec))
+	params := testParams()
+	params.Args = map[string]interface{}{"quantity": 100}
+	envelope, err := client.Resolve(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" || envelope.Tx != "beef" {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+	if req.Method != "trp.resolve" {
+		t.Errorf("expected method trp.resolve, got %q", req.Method)
+	}
+	args, _ := req.Params["args"].(map[interface{}]interface{})
+	if q, ok := args["quantity"].(uint64); !ok || q != 100 {
+		t.Errorf("expected quantity to be encoded as the CBOR integer 100, got %#v", args["quantity"])
+	}
+}
+
+func TestCBORCodecRpcError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID string `cbor:"id"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		cbor.Unmarshal(body, &req)
+		resp, _ := cbor.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"error":   map[string]interface{}{"code": -32601, "message": "method not found"},
+		})
+		w.Write(resp)
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Codec: trp.CBORCodec})
+	_, err := client.Resolve(context.Background(), testParams())
+	if !trp.IsMethodNotFound(err) {
+		t.Fatalf("expected method-not-found error, got %T: %v", err, err)
+	}
+}
+
+func TestContentTypeFromHeaders(t *testing.T) {
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithHeader("Content-Type", "application/json-rpc"))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if contentType != "application/json-rpc" {
+		t.Errorf("expected the configured Content-Type, got %q", contentType)
+	}
+}
@@ -0,0 +1,126 @@
+package trp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Standard JSON-RPC 2.0 error codes, reported in GenericRpcError.Code.
+const (
+	ErrCodeParseError     = -32700 // Invalid JSON received by the server
+	ErrCodeInvalidRequest = -32600 // Not a valid JSON-RPC request
+	ErrCodeMethodNotFound = -32601 // Method does not exist on the server
+	ErrCodeInvalidParams  = -32602 // Invalid method parameters
+	ErrCodeInternalError  = -32603 // Internal JSON-RPC error
+	ErrCodeServerError    = -32000 // Generic implementation-defined server error
+)
+
+// Diagnostic kinds sent by TRP servers in the error's data.kind member. Each
+// maps to a typed error.
+//
+// TRP defines no numeric error codes of its own beyond the JSON-RPC ones
+// above: conditions are told apart by kind. In particular there is no
+// insufficient-funds code; a wallet that cannot cover an input surfaces as
+// KindInputNotResolved, along with any other input the server could not
+// find.
+const (
+	KindUnsupportedTir     = "UnsupportedTir"     // *UnsupportedTirError
+	KindMissingTxArg       = "MissingTxArg"       // *MissingTxArgError
+	KindInputNotResolved   = "InputNotResolved"   // *InputNotResolvedError
+	KindTxScriptFailure    = "TxScriptFailure"    // *TxScriptFailureError
+	KindInvalidTirEnvelope = "InvalidTirEnvelope" // *InvalidTirEnvelopeError
+	KindInvalidTirBytes    = "InvalidTirBytes"    // *InvalidTirBytesError
+	KindUnsupportedEra     = "UnsupportedEra"     // *UnsupportedEraError
+)
+
+// RpcErrorCode returns the JSON-RPC error code carried by err, if err is or
+// wraps a *GenericRpcError.
+func RpcErrorCode(err error) (int, bool) {
+	var rpcErr *GenericRpcError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code, true
+	}
+	return 0, false
+}
+
+// IsInputNotResolved reports whether err means the server could not find
+// UTxOs matching a transaction input, e.g. because the party lacks the
+// funds it requires.
+func IsInputNotResolved(err error) bool {
+	var target *InputNotResolvedError
+	return errors.As(err, &target)
+}
+
+// IsMissingTxArg reports whether err means a required transaction argument
+// was not provided.
+func IsMissingTxArg(err error) bool {
+	var target *MissingTxArgError
+	return errors.As(err, &target)
+}
+
+// IsScriptFailure reports whether err means a script failed during
+// evaluation.
+func IsScriptFailure(err error) bool {
+	var target *TxScriptFailureError
+	return errors.As(err, &target)
+}
+
+// IsInvalidTir reports whether err means the TIR was rejected, either
+// client-side or by the server.
+func IsInvalidTir(err error) bool {
+	var (
+		client      *InvalidTirError
+		envelope    *InvalidTirEnvelopeError
+		bytes       *InvalidTirBytesError
+		unsupported *UnsupportedTirError
+	)
+	return errors.As(err, &client) || errors.As(err, &envelope) ||
+		errors.As(err, &bytes) || errors.As(err, &unsupported)
+}
+
+// IsMethodNotFound reports whether the server does not implement the called
+// method.
+func IsMethodNotFound(err error) bool {
+	code, ok := RpcErrorCode(err)
+	return ok && code == ErrCodeMethodNotFound
+}
+
+// IsTimeout reports whether err is a call or attempt that ran out of time:
+// a context deadline, a per-attempt Timeout, or a network-level timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsConnectionError reports whether err means the server could not be
+// reached or the connection failed mid-call (refused, reset, DNS failure, a
+// dropped WebSocket). Timeouts, cancellation by the caller and requests
+// that could not be encoded are not connection errors.
+func IsConnectionError(err error) bool {
+	var closedErr *ConnectionClosedError
+	if errors.As(err, &closedErr) {
+		return true
+	}
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		return false
+	}
+	return !IsTimeout(err) && !errors.Is(err, context.Canceled) && !isEncodeError(err)
+}
+
+// IsServerError reports whether err means the server failed to handle the
+// call: an HTTP 5xx response or a JSON-RPC internal error (-32603).
+// Application errors such as a failing script are not server errors.
+func IsServerError(err error) bool {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) && httpErr.Status >= http.StatusInternalServerError {
+		return true
+	}
+	code, ok := RpcErrorCode(err)
+	return ok && code == ErrCodeInternalError
+}
@@ -0,0 +1,85 @@
+package trp_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+func TestErrorHelpers(t *testing.T) {
+	wrap := func(err error) error { return fmt.Errorf("resolving transfer: %w", err) }
+
+	if !trp.IsInputNotResolved(wrap(&trp.InputNotResolvedError{Name: "source"})) {
+		t.Error("expected IsInputNotResolved for InputNotResolvedError")
+	}
+	if !trp.IsMissingTxArg(wrap(&trp.MissingTxArgError{Key: "quantity"})) {
+		t.Error("expected IsMissingTxArg for MissingTxArgError")
+	}
+	if !trp.IsScriptFailure(wrap(&trp.TxScriptFailureError{})) {
+		t.Error("expected IsScriptFailure for TxScriptFailureError")
+	}
+	for _, err := range []error{&trp.InvalidTirBytesError{}, &trp.UnsupportedTirError{}, &trp.InvalidTirError{}} {
+		if !trp.IsInvalidTir(wrap(err)) {
+			t.Errorf("expected IsInvalidTir for %T", err)
+		}
+	}
+
+	notFound := wrap(&trp.GenericRpcError{Code: trp.ErrCodeMethodNotFound, Message: "nope"})
+	if !trp.IsMethodNotFound(notFound) {
+		t.Error("expected IsMethodNotFound for code -32601")
+	}
+	if code, ok := trp.RpcErrorCode(notFound); !ok || code != -32601 {
+		t.Errorf("expected code -32601, got %d (%v)", code, ok)
+	}
+
+	other := wrap(&trp.NetworkError{})
+	if trp.IsInputNotResolved(other) || trp.IsMethodNotFound(other) || trp.IsInvalidTir(other) {
+		t.Error("helpers must not match unrelated errors")
+	}
+	if _, ok := trp.RpcErrorCode(other); ok {
+		t.Error("expected no code for a network error")
+	}
+}
+
+func TestTransportErrorHelpers(t *testing.T) {
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+	_, err := trp.NewClient(trp.ClientOptions{Endpoint: refused.URL}).Resolve(context.Background(), testParams())
+	if !trp.IsConnectionError(err) || trp.IsTimeout(err) || trp.IsServerError(err) {
+		t.Errorf("expected a connection error for a refused connection, got %v", err)
+	}
+
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }))
+	defer slow.Close()
+	defer close(release)
+	_, err = trp.NewClient(trp.ClientOptions{Endpoint: slow.URL, Timeout: 10 * time.Millisecond}).Resolve(context.Background(), testParams())
+	if !trp.IsTimeout(err) || trp.IsConnectionError(err) {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = trp.NewClient(trp.ClientOptions{Endpoint: slow.URL}).Resolve(ctx, testParams())
+	if trp.IsTimeout(err) || trp.IsConnectionError(err) {
+		t.Errorf("expected cancellation to be neither a timeout nor a connection error, got %v", err)
+	}
+
+	if !trp.IsServerError(&trp.HttpError{Status: 503}) || trp.IsServerError(&trp.HttpError{Status: 404}) {
+		t.Error("expected IsServerError for 5xx responses only")
+	}
+	if !trp.IsServerError(&trp.GenericRpcError{Code: trp.ErrCodeInternalError}) {
+		t.Error("expected IsServerError for a JSON-RPC internal error")
+	}
+	if trp.IsServerError(&trp.TxScriptFailureError{}) {
+		t.Error("application errors must not be server errors")
+	}
+	if !trp.IsConnectionError(&trp.ConnectionClosedError{}) {
+		t.Error("expected IsConnectionError for a dropped WebSocket")
+	}
+}
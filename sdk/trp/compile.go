@@ -0,0 +1,88 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// methodCompile is the TRP extension that compiles tx3 source into a TIR
+// envelope. Servers without it answer with a method-not-found error,
+// surfaced as *UnsupportedMethodError.
+const methodCompile = "trp.compile"
+
+// StageCompile is the CompileAndResolve step that turns source into TIR,
+// reported in StageError.Stage.
+const StageCompile = "compile"
+
+type compileParams struct {
+	Source string `json:"source"`
+}
+
+// CompileAndResolve compiles tx3 source through the server's trp.compile
+// extension and resolves the resulting TIR with args, which must encode as
+// a JSON object (e.g. core.ArgMap). It is meant for prototyping; production
+// code should ship precompiled TIR.
+//
+// Failures are returned as *StageError: StageCompile for the compile call,
+// including servers that do not implement it, and StageResolve for the
+// resolve. opts apply to both calls.
+func (c *Client) CompileAndResolve(ctx context.Context, source string, args interface{}, opts ...CallOption) (*TxEnvelope, error) {
+	tir, err := c.compile(ctx, source, newCallOptions(opts))
+	if err != nil {
+		return nil, &StageError{Stage: StageCompile, Cause: err}
+	}
+	argMap, err := toArgMap(args)
+	if err != nil {
+		return nil, &StageError{Stage: StageResolve, Cause: err}
+	}
+	envelope, err := c.Resolve(ctx, ResolveParams{Tir: tir, Args: argMap}, opts...)
+	if err != nil {
+		return nil, &StageError{Stage: StageResolve, Cause: err}
+	}
+	return envelope, nil
+}
+
+// compile calls trp.compile. Compiling is a pure function of the source, so
+// the call is retried like a resolve.
+func (c *Client) compile(ctx context.Context, source string, co callOptions) (core.TirEnvelope, error) {
+	result, err := c.call(ctx, methodCompile, compileParams{Source: source}, true, co)
+	if IsMethodNotFound(err) {
+		return core.TirEnvelope{}, &UnsupportedMethodError{Method: methodCompile, Cause: err}
+	}
+	if err != nil {
+		return core.TirEnvelope{}, err
+	}
+	var tir core.TirEnvelope
+	if err := json.Unmarshal(result, &tir); err != nil {
+		return core.TirEnvelope{}, &DeserializationError{Cause: err, Raw: string(result)}
+	}
+	if err := tir.Validate(); err != nil {
+		return core.TirEnvelope{}, &InvalidTirError{Cause: err}
+	}
+	return tir, nil
+}
+
+// toArgMap converts CompileAndResolve args to the map ResolveParams
+// carries, through their JSON encoding unless they already are one.
+func toArgMap(args interface{}) (map[string]interface{}, error) {
+	switch a := args.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return a, nil
+	case core.ArgMap:
+		return a, nil
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, &NetworkError{Cause: &encodeError{cause: err}}
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, &NetworkError{Cause: &encodeError{cause: fmt.Errorf("args must encode as a JSON object: %w", err)}}
+	}
+	return out, nil
+}
@@ -0,0 +1,61 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestCompileAndResolve(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.compile": map[string]interface{}{"content": "aabb", "encoding": "hex", "version": "v1beta0"},
+		"trp.resolve": map[string]interface{}{"hash": "abc", "tx": "beef"},
+	})
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	envelope, err := client.CompileAndResolve(context.Background(), "tx transfer() {}", core.ArgMap{"quantity": 1})
+	if err != nil {
+		t.Fatalf("CompileAndResolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+}
+
+func TestCompileAndResolveReportsStage(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		results map[string]interface{}
+		stage   string
+		cause   func(error) bool
+	}{
+		{"compile error", map[string]interface{}{
+			"trp.compile": &trptest.Error{Code: -32000, Message: "syntax error at 1:4"},
+		}, trp.StageCompile, func(err error) bool {
+			var rpcErr *trp.GenericRpcError
+			return errors.As(err, &rpcErr) && rpcErr.Message == "syntax error at 1:4"
+		}},
+		{"unsupported", map[string]interface{}{}, trp.StageCompile, func(err error) bool {
+			var unsupported *trp.UnsupportedMethodError
+			return errors.As(err, &unsupported)
+		}},
+		{"resolve error", map[string]interface{}{
+			"trp.compile": map[string]interface{}{"content": "aabb", "encoding": "hex", "version": "v1beta0"},
+		}, trp.StageResolve, trp.IsMethodNotFound},
+	} {
+		server := trptest.NewMethodServer(tc.results)
+		client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+		_, err := client.CompileAndResolve(context.Background(), "tx transfer() {}", nil)
+		server.Close()
+
+		var stageErr *trp.StageError
+		if !errors.As(err, &stageErr) || stageErr.Stage != tc.stage || !tc.cause(err) {
+			t.Errorf("%s: expected a %s StageError, got %v", tc.name, tc.stage, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package trp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// gzipBytes compresses b with gzip.
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gzipMagic starts every gzip stream. Neither JSON nor CBOR responses can
+// begin with it.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readBody reads the full response body, decompressing it when it is
+// gzip-compressed. The content decides rather than the Content-Encoding
+// header, so servers that ignore Accept-Encoding, or label a plain body
+// gzip, are still read. A positive limit caps the (decompressed) size;
+// exceeding it yields *ResponseTooLargeError. A non-nil progress sees the
+// (decompressed) bytes as they are read.
+func readBody(resp *http.Response, limit int64, progress io.Writer) ([]byte, error) {
+	br := bufio.NewReader(resp.Body)
+	var r io.Reader = br
+	compressed := false
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, decompressError(resp, err)
+		}
+		defer zr.Close()
+		r, compressed = zr, true
+	}
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	if progress != nil {
+		r = io.TeeReader(r, progress)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		if compressed {
+			return nil, decompressError(resp, err)
+		}
+		return nil, err
+	}
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+	return body, nil
+}
+
+// decompressError adds the Content-Encoding the server declared to a gzip
+// failure.
+func decompressError(resp *http.Response, err error) error {
+	return fmt.Errorf("failed to decompress response (Content-Encoding %q): %w", resp.Header.Get("Content-Encoding"), err)
+}
@@ -0,0 +1,118 @@
+package trp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestGzipRequestAndResponse(t *testing.T) {
+	var receivedMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected gzip request body, got Content-Encoding %q", r.Header.Get("Content-Encoding"))
+		}
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("request body is not gzip: %v", err)
+			return
+		}
+		var req map[string]json.RawMessage
+		json.NewDecoder(zr).Decode(&req)
+		json.Unmarshal(req["method"], &receivedMethod)
+
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		json.NewEncoder(zw).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      id,
+			"result":  map[string]interface{}{"hash": "abc", "tx": "beef"},
+		})
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Compression: true})
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" {
+		t.Errorf("expected hash 'abc', got %q", envelope.Hash)
+	}
+	if receivedMethod != "trp.resolve" {
+		t.Errorf("expected method 'trp.resolve', got %q", receivedMethod)
+	}
+}
+
+func TestUncompressedResponseAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		io.Copy(io.Discard, r.Body)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Compression: true})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+}
+
+func TestCorruptGzipResponse(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body []byte
+	}{
+		{"bad header", []byte{0x1f, 0x8b, 0x00, 0x00}},
+		{"truncated stream", func() []byte {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			zw.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"hash":"abc","tx":"beef"}}`))
+			zw.Close()
+			return buf.Bytes()[:buf.Len()-6]
+		}()},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(tc.body)
+		}))
+
+		client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Compression: true})
+		_, err := client.Resolve(context.Background(), testParams())
+		server.Close()
+		var netErr *trp.NetworkError
+		if !errors.As(err, &netErr) || !strings.Contains(err.Error(), `failed to decompress response (Content-Encoding "gzip")`) {
+			t.Errorf("%s: expected a decompression NetworkError, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestPlainResponseLabelledGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		w.Header().Set("Content-Encoding", "gzip")
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Compression: true})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+}
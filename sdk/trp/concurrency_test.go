@@ -0,0 +1,108 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+// TestConcurrentResolve shares one client across goroutines that mix per-call
+// options with client defaults. Run with -race to check for data races.
+func TestConcurrentResolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	headers := map[string]string{"X-Client": "a"}
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Headers:  headers,
+		EnvArgs:  map[string]interface{}{"network": "preprod"},
+	})
+	// Mutating the caller's map after construction must not race with calls.
+	headers["X-Client"] = "b"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			params := testParams()
+			params.Env = map[string]interface{}{"slot": i}
+			var opts []trp.CallOption
+			if i%2 == 0 {
+				opts = append(opts, trp.WithCallHeader("X-Call", "x"))
+			}
+			if _, err := client.Resolve(context.Background(), params, opts...); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Resolve failed: %v", err)
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithMaxConcurrentRequests(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+				t.Errorf("Resolve failed: %v", err)
+			}
+		}()
+	}
+
+	// With both slots taken, a further call waits until its context ends.
+	for inFlight.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var limitErr *trp.ConcurrencyLimitError
+	if _, err := client.Resolve(ctx, testParams()); !errors.As(err, &limitErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected ConcurrencyLimitError, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if got := peak.Load(); got != 2 {
+		t.Errorf("expected at most 2 requests in flight, peak was %d", got)
+	}
+
+	var invalid *trp.InvalidOptionsError
+	if err := (trp.ClientOptions{MaxConcurrentRequests: -1}).Validate(); !errors.As(err, &invalid) || invalid.Option != "MaxConcurrentRequests" {
+		t.Errorf("expected InvalidOptionsError for MaxConcurrentRequests, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package trp
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// OptionsFromEnv builds ClientOptions from environment variables named after
+// prefix:
+//
+//	<PREFIX>_ENDPOINT       Endpoint
+//	<PREFIX>_TIMEOUT        Timeout, as a Go duration (e.g. "30s")
+//	<PREFIX>_HEADER_<NAME>  Headers; underscores in NAME become hyphens,
+//	                        so TRP_HEADER_DMTR_API_KEY sets "Dmtr-Api-Key"
+//
+// Unset variables leave the corresponding fields at their zero values.
+func OptionsFromEnv(prefix string) (ClientOptions, error) {
+	var options ClientOptions
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	options.Endpoint = os.Getenv(prefix + "ENDPOINT")
+
+	if raw, ok := os.LookupEnv(prefix + "TIMEOUT"); ok && raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil {
+			return ClientOptions{}, fmt.Errorf("invalid %sTIMEOUT %q: expected a duration such as \"30s\": %w", prefix, raw, err)
+		}
+		options.Timeout = timeout
+	}
+
+	headerPrefix := prefix + "HEADER_"
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(key, headerPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		if options.Headers == nil {
+			options.Headers = make(map[string]string)
+		}
+		options.Headers[http.CanonicalHeaderKey(strings.ReplaceAll(name, "_", "-"))] = value
+	}
+
+	return options, nil
+}
@@ -0,0 +1,48 @@
+package trp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv("MYTRP_ENDPOINT", "https://trp.example")
+	t.Setenv("MYTRP_TIMEOUT", "45s")
+	t.Setenv("MYTRP_HEADER_DMTR_API_KEY", "secret")
+
+	options, err := trp.OptionsFromEnv("MYTRP")
+	if err != nil {
+		t.Fatalf("OptionsFromEnv failed: %v", err)
+	}
+	if options.Endpoint != "https://trp.example" {
+		t.Errorf("unexpected endpoint %q", options.Endpoint)
+	}
+	if options.Timeout != 45*time.Second {
+		t.Errorf("unexpected timeout %s", options.Timeout)
+	}
+	if options.Headers["Dmtr-Api-Key"] != "secret" || len(options.Headers) != 1 {
+		t.Errorf("unexpected headers %v", options.Headers)
+	}
+}
+
+func TestOptionsFromEnvUnset(t *testing.T) {
+	options, err := trp.OptionsFromEnv("UNSET_TRP_PREFIX")
+	if err != nil {
+		t.Fatalf("OptionsFromEnv failed: %v", err)
+	}
+	if options.Endpoint != "" || options.Timeout != 0 || options.Headers != nil {
+		t.Errorf("expected zero options, got %+v", options)
+	}
+}
+
+func TestOptionsFromEnvInvalidTimeout(t *testing.T) {
+	t.Setenv("MYTRP_TIMEOUT", "soon")
+
+	_, err := trp.OptionsFromEnv("MYTRP")
+	if err == nil || !strings.Contains(err.Error(), "MYTRP_TIMEOUT") {
+		t.Fatalf("expected descriptive timeout error, got %v", err)
+	}
+}
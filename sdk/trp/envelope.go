@@ -0,0 +1,86 @@
+package trp
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// Bytes decodes Tx into raw CBOR bytes. An explicit Encoding is honoured;
+// otherwise hex is tried first (the TRP default), then standard base64.
+// With Lenient set, a Tx labelled base64 that does not decode as such is
+// retried as hex.
+func (e TxEnvelope) Bytes() ([]byte, error) {
+	switch e.Encoding {
+	case core.EncodingHex:
+		b, err := hex.DecodeString(e.Tx)
+		if err != nil {
+			return nil, fmt.Errorf("tx is not valid hex: %w", err)
+		}
+		return b, nil
+	case core.EncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(e.Tx)
+		if err == nil {
+			return b, nil
+		}
+		if !e.Lenient {
+			return nil, fmt.Errorf("tx is not valid base64: %w", err)
+		}
+		b, hexErr := hex.DecodeString(e.Tx)
+		if hexErr != nil {
+			return nil, fmt.Errorf("tx is not valid base64 (%v) nor, leniently, hex (%v)", err, hexErr)
+		}
+		return b, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("unsupported tx encoding %q (supported: %s, %s)", e.Encoding, core.EncodingHex, core.EncodingBase64)
+	}
+
+	if e.Tx == "" {
+		return nil, errors.New("tx is empty")
+	}
+	if b, err := hex.DecodeString(e.Tx); err == nil {
+		return b, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(e.Tx); err == nil {
+		return b, nil
+	}
+	return nil, errors.New("tx is neither valid hex nor valid base64")
+}
+
+// ForCIP30 returns the transaction as lowercase hex-encoded CBOR, the
+// format CIP-30 wallets take in signTx, whatever encoding the server used
+// for Tx. It fails if Tx does not decode or is not a CBOR transaction.
+func (e TxEnvelope) ForCIP30() (string, error) {
+	tx, err := e.Bytes()
+	if err != nil {
+		return "", err
+	}
+	if _, err := cborTxBody(tx); err != nil {
+		return "", fmt.Errorf("tx is not a valid CBOR transaction: %w", err)
+	}
+	return hex.EncodeToString(tx), nil
+}
+
+// txHashLen is the length of a hex-encoded transaction id (Blake2b-256).
+const txHashLen = 64
+
+// validateEnvelope checks that a resolve result is well formed: a non-empty
+// Tx and a Hash of 64 hex digits.
+func validateEnvelope(e *TxEnvelope) error {
+	switch {
+	case e.Tx == "":
+		return &InvalidEnvelopeError{Field: "tx", Reason: "is empty"}
+	case e.Hash == "":
+		return &InvalidEnvelopeError{Field: "hash", Reason: "is empty"}
+	case len(e.Hash) != txHashLen:
+		return &InvalidEnvelopeError{Field: "hash", Reason: fmt.Sprintf("has %d characters, want %d", len(e.Hash), txHashLen)}
+	}
+	if _, err := hex.DecodeString(e.Hash); err != nil {
+		return &InvalidEnvelopeError{Field: "hash", Reason: "is not valid hex"}
+	}
+	return nil
+}
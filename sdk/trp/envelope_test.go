@@ -0,0 +1,163 @@
+package trp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestTxEnvelopeBytes(t *testing.T) {
+	want := []byte{0x84, 0xa0, 0xff, 0xf5}
+	cases := map[string]trp.TxEnvelope{
+		"hex detected":    {Tx: "84a0fff5"},
+		"base64 detected": {Tx: "hKD/9Q=="},
+		"explicit base64": {Tx: "hKD/9Q==", Encoding: "base64"},
+		"explicit hex":    {Tx: "84A0FFF5", Encoding: "hex"},
+	}
+	for name, envelope := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := envelope.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes failed: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("expected %x, got %x", want, got)
+			}
+		})
+	}
+}
+
+func TestTxEnvelopeBytesErrors(t *testing.T) {
+	cases := map[string]struct {
+		envelope trp.TxEnvelope
+		wantErr  string
+	}{
+		"empty":            {trp.TxEnvelope{}, "empty"},
+		"garbage":          {trp.TxEnvelope{Tx: "not*tx"}, "neither valid hex nor valid base64"},
+		"wrong explicit":   {trp.TxEnvelope{Tx: "hKD/9Q==", Encoding: "hex"}, "not valid hex"},
+		"unknown encoding": {trp.TxEnvelope{Tx: "84", Encoding: "utf8"}, "unsupported tx encoding"},
+		"mislabelled":      {trp.TxEnvelope{Tx: "84a0ff", Encoding: "base64"}, "not valid base64"},
+		"lenient garbage":  {trp.TxEnvelope{Tx: "not*tx", Encoding: "base64", Lenient: true}, "nor, leniently, hex"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.envelope.Bytes()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEnvelope(t *testing.T) {
+	validHash := strings.Repeat("ab", 32)
+	cases := []struct {
+		name     string
+		hash, tx string
+		field    string // empty if the envelope is valid
+	}{
+		{"valid", validHash, "84a0fff5", ""},
+		{"empty tx", validHash, "", "tx"},
+		{"empty hash", "", "84a0fff5", "hash"},
+		{"short hash", "abc", "84a0fff5", "hash"},
+		{"non-hex hash", strings.Repeat("zz", 32), "84a0fff5", "hash"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := envelopeServer(t, tc.hash, tc.tx)
+
+			client := trp.NewClientWithOptions(server.URL, trp.WithValidateEnvelope())
+			_, err := client.Resolve(context.Background(), testParams())
+			if tc.field == "" {
+				if err != nil {
+					t.Fatalf("Resolve failed: %v", err)
+				}
+				return
+			}
+			var invalid *trp.InvalidEnvelopeError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected InvalidEnvelopeError, got %T: %v", err, err)
+			}
+			if invalid.Field != tc.field {
+				t.Errorf("expected field %q, got %q", tc.field, invalid.Field)
+			}
+
+			lenient := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+			if _, err := lenient.Resolve(context.Background(), testParams()); err != nil {
+				t.Errorf("expected no validation by default, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTxEnvelopeBytesLenient(t *testing.T) {
+	b, err := trp.TxEnvelope{Tx: "84a0ff", Encoding: "base64", Lenient: true}.Bytes()
+	if err != nil || !bytes.Equal(b, []byte{0x84, 0xa0, 0xff}) {
+		t.Fatalf("expected mislabelled hex to decode leniently, got %x (err %v)", b, err)
+	}
+}
+
+func TestResolveReportsFeeAndExecutionUnits(t *testing.T) {
+	for _, tc := range []struct {
+		result              map[string]interface{}
+		wantFee, wantBudget bool
+	}{
+		{map[string]interface{}{"hash": "abc", "tx": "beef", "fee": 170000, "executionUnits": map[string]interface{}{"mem": 1000, "steps": 2000}}, true, true},
+		{map[string]interface{}{"hash": "abc", "tx": "beef"}, false, false},
+	} {
+		server := trptest.NewMethodServer(map[string]interface{}{"trp.resolve": tc.result})
+		envelope, err := trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Resolve(context.Background(), testParams())
+		server.Close()
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if (envelope.Fee != nil) != tc.wantFee || (envelope.ExecutionUnits != nil) != tc.wantBudget {
+			t.Fatalf("unexpected fee %v or execution units %v", envelope.Fee, envelope.ExecutionUnits)
+		}
+		if tc.wantFee && (*envelope.Fee != 170000 || *envelope.ExecutionUnits != (trp.ExecutionUnits{Mem: 1000, Steps: 2000})) {
+			t.Errorf("unexpected fee %d or execution units %+v", *envelope.Fee, *envelope.ExecutionUnits)
+		}
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.resolve": map[string]interface{}{"hash": "abc", "tx": "beef", "collateral": "x"},
+	})
+	defer server.Close()
+
+	if _, err := trp.NewClientWithOptions(server.URL).Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("expected unknown fields to be ignored by default, got %v", err)
+	}
+	_, err := trp.NewClientWithOptions(server.URL, trp.WithStrictDecoding()).Resolve(context.Background(), testParams())
+	var decodeErr *trp.DeserializationError
+	if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), `unknown field "collateral"`) {
+		t.Errorf("expected DeserializationError naming the field, got %v", err)
+	}
+}
+
+func TestTxEnvelopeForCIP30(t *testing.T) {
+	for _, envelope := range []trp.TxEnvelope{
+		{Tx: "84A0A0F5F6", Encoding: "hex"},
+		{Tx: "hKCg9fY=", Encoding: "base64"},
+	} {
+		got, err := envelope.ForCIP30()
+		if err != nil {
+			t.Fatalf("ForCIP30 failed: %v", err)
+		}
+		if got != "84a0a0f5f6" {
+			t.Errorf("expected lowercase hex CBOR, got %q", got)
+		}
+	}
+
+	for _, envelope := range []trp.TxEnvelope{{}, {Tx: "01"}} {
+		if _, err := envelope.ForCIP30(); err == nil {
+			t.Errorf("expected %q to be rejected", envelope.Tx)
+		}
+	}
+}
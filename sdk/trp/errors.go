@@ -1,8 +1,13 @@
 package trp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // TrpError is the marker interface for all TRP-related errors.
@@ -11,27 +16,202 @@ type TrpError interface {
 	isTrpError()
 }
 
-// NetworkError indicates a connection or transport failure.
+// NetworkError indicates a connection or transport failure. RemoteAddr is
+// the address the failed HTTP request was connected, or last tried to
+// connect, to; it is empty when no connection was attempted.
 type NetworkError struct {
-	Cause error
+	Cause      error
+	RemoteAddr string
 }
 
 func (e *NetworkError) Error() string  { return fmt.Sprintf("TRP network error: %v", e.Cause) }
 func (e *NetworkError) Unwrap() error  { return e.Cause }
 func (e *NetworkError) isTrpError()    {}
 
-// HttpError indicates a non-200 HTTP response from the TRP server.
+// HttpError indicates a non-200 HTTP response from the TRP server. Header
+// carries the response headers, e.g. to honour Retry-After on a 429.
+// RemoteAddr is the address of the backend (or proxy) that answered, to
+// tell apart the nodes behind a load-balanced endpoint.
 type HttpError struct {
 	Status     int
 	StatusText string
 	Body       string
+	Header     http.Header
+	RemoteAddr string
 }
 
 func (e *HttpError) Error() string {
-	return fmt.Sprintf("TRP HTTP error %d %s: %s", e.Status, e.StatusText, e.Body)
+	text := strings.TrimSpace(strings.TrimPrefix(e.StatusText, strconv.Itoa(e.Status)))
+	if text == "" {
+		text = http.StatusText(e.Status)
+	}
+	return fmt.Sprintf("TRP HTTP error %d %s: %s", e.Status, text, strings.TrimSpace(e.Body))
 }
 func (e *HttpError) isTrpError() {}
 
+// ResponseTooLargeError indicates a response body larger than
+// ClientOptions.MaxResponseBytes. The body is discarded.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("TRP response too large: exceeds %d bytes", e.Limit)
+}
+func (e *ResponseTooLargeError) isTrpError() {}
+
+// RequestTooLargeError indicates an encoded request body larger than
+// ClientOptions.MaxRequestBytes. The request is not sent.
+type RequestTooLargeError struct {
+	Size  int64
+	Limit int64
+}
+
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("TRP request too large: %d bytes exceeds the %d byte limit", e.Size, e.Limit)
+}
+func (e *RequestTooLargeError) isTrpError() {}
+
+// TokenProviderError indicates the configured TokenProvider failed to supply
+// a bearer token. The request is not sent.
+type TokenProviderError struct {
+	Cause error
+}
+
+func (e *TokenProviderError) Error() string {
+	return fmt.Sprintf("TRP token provider failed: %v", e.Cause)
+}
+func (e *TokenProviderError) Unwrap() error { return e.Cause }
+func (e *TokenProviderError) isTrpError()   {}
+
+// UnsupportedMethodError indicates the server does not implement an optional
+// method the client relies on, such as trp.capabilities. Cause is the
+// server's method-not-found error.
+type UnsupportedMethodError struct {
+	Method string
+	Cause  error
+}
+
+func (e *UnsupportedMethodError) Error() string {
+	return fmt.Sprintf("TRP server does not support %s", e.Method)
+}
+func (e *UnsupportedMethodError) Unwrap() error { return e.Cause }
+func (e *UnsupportedMethodError) isTrpError()   {}
+
+// CircuitOpenError indicates a request was not sent because the endpoint's
+// circuit breaker is open after repeated failures. RetryAfter is the time
+// left until a probe request is allowed; it is zero while another probe is
+// in flight.
+type CircuitOpenError struct {
+	Endpoint   string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("TRP circuit open for %s: retry in %s", e.Endpoint, e.RetryAfter.Round(time.Millisecond))
+	}
+	return fmt.Sprintf("TRP circuit open for %s", e.Endpoint)
+}
+func (e *CircuitOpenError) isTrpError() {}
+
+// InvalidOptionsError indicates a ClientOptions setting that cannot be used.
+type InvalidOptionsError struct {
+	Option string
+	Cause  error
+}
+
+func (e *InvalidOptionsError) Error() string {
+	return fmt.Sprintf("invalid TRP client option %s: %v", e.Option, e.Cause)
+}
+func (e *InvalidOptionsError) Unwrap() error { return e.Cause }
+func (e *InvalidOptionsError) isTrpError()   {}
+
+// CertificatePinError indicates the TLS server presented no certificate
+// matching ClientOptions.PinnedCertSHA256. Presented lists the SHA-256
+// fingerprints (hex) of the certificates it did present, leaf first. The
+// call is not retried.
+type CertificatePinError struct {
+	Host      string
+	Presented []string
+}
+
+func (e *CertificatePinError) Error() string {
+	return fmt.Sprintf("TRP server %s presented no pinned certificate (got %s)", e.Host, strings.Join(e.Presented, ", "))
+}
+func (e *CertificatePinError) isTrpError() {}
+
+// SignerError indicates the configured Signer rejected or failed to sign a
+// request. The request is not sent.
+type SignerError struct {
+	Cause error
+}
+
+func (e *SignerError) Error() string {
+	return fmt.Sprintf("TRP request signer failed: %v", e.Cause)
+}
+func (e *SignerError) Unwrap() error { return e.Cause }
+func (e *SignerError) isTrpError()   {}
+
+// EnvProviderError indicates the configured EnvProvider failed to supply env
+// values. The request is not sent.
+type EnvProviderError struct {
+	Cause error
+}
+
+func (e *EnvProviderError) Error() string {
+	return fmt.Sprintf("TRP env provider failed: %v", e.Cause)
+}
+func (e *EnvProviderError) Unwrap() error { return e.Cause }
+func (e *EnvProviderError) isTrpError()   {}
+
+// StageError indicates which step of ResolveAndSubmit (StageResolve,
+// StageSign or StageSubmit) or CompileAndResolve (StageCompile or
+// StageResolve) failed. Cause is the error that step returned.
+type StageError struct {
+	Stage string
+	Cause error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("TRP %s step failed: %v", e.Stage, e.Cause)
+}
+func (e *StageError) Unwrap() error { return e.Cause }
+func (e *StageError) isTrpError()   {}
+
+// BatchDeadlineError is reported for ResolveBatch items that were not sent
+// because too little time was left before the context deadline to finish
+// another sub-batch; see ClientOptions.MaxBatchSize. Remaining is the time
+// that was left. It matches context.DeadlineExceeded, so IsTimeout reports
+// true.
+type BatchDeadlineError struct {
+	Remaining time.Duration
+}
+
+func (e *BatchDeadlineError) Error() string {
+	return fmt.Sprintf("TRP batch item not sent: only %s left before the deadline", e.Remaining)
+}
+func (e *BatchDeadlineError) Unwrap() error { return context.DeadlineExceeded }
+func (e *BatchDeadlineError) isTrpError()   {}
+
+// EndpointsExhaustedError indicates every configured endpoint failed during
+// failover. Errors holds one failure per endpoint, in the order tried, and
+// is exposed through Unwrap so errors.As reaches the individual causes.
+type EndpointsExhaustedError struct {
+	Endpoints []string
+	Errors    []error
+}
+
+func (e *EndpointsExhaustedError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %v", e.Endpoints[i], err)
+	}
+	return fmt.Sprintf("all %d TRP endpoints failed: %s", len(e.Errors), strings.Join(parts, "; "))
+}
+func (e *EndpointsExhaustedError) Unwrap() []error { return e.Errors }
+func (e *EndpointsExhaustedError) isTrpError()     {}
+
 // DeserializationError indicates failure to parse a TRP response.
 type DeserializationError struct {
 	Cause error
@@ -54,19 +234,128 @@ func (e *MalformedResponseError) Error() string {
 }
 func (e *MalformedResponseError) isTrpError() {}
 
+// InvalidEnvelopeError indicates a successful resolve whose envelope is
+// unusable, as detected by ClientOptions.ValidateEnvelope.
+type InvalidEnvelopeError struct {
+	Field  string // "tx" or "hash"
+	Reason string
+}
+
+func (e *InvalidEnvelopeError) Error() string {
+	return fmt.Sprintf("TRP returned an invalid envelope: %s %s", e.Field, e.Reason)
+}
+func (e *InvalidEnvelopeError) isTrpError() {}
+
+// TxHashMismatchError indicates that the hash advertised by the server does
+// not match the transaction it returned (see ClientOptions.VerifyHash).
+type TxHashMismatchError struct {
+	Advertised string
+	Computed   string
+}
+
+func (e *TxHashMismatchError) Error() string {
+	return fmt.Sprintf("tx hash mismatch: server advertised %s, tx body hashes to %s", e.Advertised, e.Computed)
+}
+func (e *TxHashMismatchError) isTrpError() {}
+
+// RateLimitError indicates that the configured RateLimiter refused to admit a
+// request, typically because the context was cancelled while waiting.
+type RateLimitError struct {
+	Cause error
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("TRP rate limiter: %v", e.Cause)
+}
+func (e *RateLimitError) Unwrap() error { return e.Cause }
+func (e *RateLimitError) isTrpError()   {}
+
+// ConcurrencyLimitError indicates that a request gave up waiting for one of
+// the MaxConcurrentRequests slots because its context was done.
+type ConcurrencyLimitError struct {
+	Limit int
+	Cause error
+}
+
+func (e *ConcurrencyLimitError) Error() string {
+	return fmt.Sprintf("TRP concurrency limit: waiting for one of %d request slots: %v", e.Limit, e.Cause)
+}
+func (e *ConcurrencyLimitError) Unwrap() error { return e.Cause }
+func (e *ConcurrencyLimitError) isTrpError()   {}
+
+// ConnectionClosedError indicates that a WebSocket connection dropped before
+// the server answered. The next call dials a new connection.
+type ConnectionClosedError struct {
+	Cause error
+}
+
+func (e *ConnectionClosedError) Error() string {
+	return fmt.Sprintf("TRP WebSocket connection closed: %v", e.Cause)
+}
+func (e *ConnectionClosedError) Unwrap() error { return e.Cause }
+func (e *ConnectionClosedError) isTrpError()   {}
+
+// ClientClosedError indicates a call on a client after Close.
+type ClientClosedError struct{}
+
+func (e *ClientClosedError) Error() string {
+	return "TRP client closed"
+}
+func (e *ClientClosedError) isTrpError() {}
+
+// ResponseMismatchError indicates a response that does not match the request
+// it answers: a different JSON-RPC version, or a different id, which points
+// at a server bug or a proxy crossing up responses.
+type ResponseMismatchError struct {
+	Field    string // "jsonrpc" or "id"
+	Sent     string
+	Received string
+}
+
+func (e *ResponseMismatchError) Error() string {
+	return fmt.Sprintf("TRP protocol error: response %s %q does not match request %s %q", e.Field, e.Received, e.Field, e.Sent)
+}
+func (e *ResponseMismatchError) isTrpError() {}
+
 // GenericRpcError represents a JSON-RPC error object returned by the server.
+// Code carries the server's numeric error code so callers can branch on it
+// without matching the message. Data holds the raw error detail; use
+// UnmarshalData to decode it into a concrete type.
 type GenericRpcError struct {
 	Code    int
 	Message string
 	Data    json.RawMessage
+
+	format func(code int, message string, data interface{}) string // ClientOptions.ErrorFormatter
 }
 
 func (e *GenericRpcError) Error() string {
+	if e.format != nil {
+		var data interface{}
+		if len(e.Data) > 0 {
+			json.Unmarshal(e.Data, &data)
+		}
+		return e.format(e.Code, e.Message, data)
+	}
+	if e.Code == 0 {
+		return fmt.Sprintf("TRP RPC error: %s", e.Message)
+	}
 	return fmt.Sprintf("TRP RPC error %d: %s", e.Code, e.Message)
 }
 func (e *GenericRpcError) isTrpError() {}
 
-// UnsupportedTirError indicates TIR version mismatch.
+// UnmarshalData decodes the server's error detail into v. It returns an error
+// if the server sent no data.
+func (e *GenericRpcError) UnmarshalData(v interface{}) error {
+	if len(e.Data) == 0 || string(e.Data) == "null" {
+		return fmt.Errorf("TRP RPC error %d carries no data", e.Code)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// UnsupportedTirError indicates TIR version mismatch. It comes from the
+// server, or from the client when ClientOptions.CompatibleTirVersions
+// excludes the TIR's version.
 type UnsupportedTirError struct {
 	Expected string
 	Provided string
@@ -108,6 +397,16 @@ func (e *TxScriptFailureError) Error() string {
 }
 func (e *TxScriptFailureError) isTrpError() {}
 
+// InvalidTirError indicates a TIR envelope rejected client-side, before any
+// request was sent (unsupported encoding or undecodable content).
+type InvalidTirError struct {
+	Cause error
+}
+
+func (e *InvalidTirError) Error() string { return fmt.Sprintf("invalid TIR: %v", e.Cause) }
+func (e *InvalidTirError) Unwrap() error { return e.Cause }
+func (e *InvalidTirError) isTrpError()   {}
+
 // InvalidTirEnvelopeError indicates a malformed TIR envelope.
 type InvalidTirEnvelopeError struct{}
 
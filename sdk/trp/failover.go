@@ -0,0 +1,90 @@
+package trp
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+)
+
+// EndpointOrder controls the order in which a Client tries its endpoints.
+type EndpointOrder int
+
+const (
+	// EndpointsInOrder tries the endpoints as listed: primary, then backups.
+	EndpointsInOrder EndpointOrder = iota
+	// EndpointsRandom shuffles the endpoints on every call to spread load.
+	EndpointsRandom
+)
+
+// EndpointConfig is a server of ClientOptions.EndpointConfigs with the
+// headers and credentials sent only to it.
+type EndpointConfig struct {
+	URL string
+
+	// Headers are set on requests to this endpoint, over
+	// ClientOptions.Headers.
+	Headers map[string]string
+
+	// BearerToken or BasicAuth, when set, replaces the client-wide
+	// Authorization header (including one from TokenProvider) for this
+	// endpoint. BearerToken takes precedence.
+	BearerToken string
+	BasicAuth   *BasicAuth
+}
+
+// endpoints returns the configured endpoint list.
+func (c *Client) endpoints() []string {
+	if len(c.options.Endpoints) > 0 {
+		return c.options.Endpoints
+	}
+	return []string{c.options.Endpoint}
+}
+
+// deliver posts out to the configured endpoints. Idempotent calls fail over
+// to the next endpoint on connection errors and 5xx responses; other calls
+// go to the first endpoint in order only.
+func (c *Client) deliver(ctx context.Context, out outgoing) ([]byte, error) {
+	endpoints := c.endpoints()
+	if c.options.EndpointOrder == EndpointsRandom && len(endpoints) > 1 {
+		shuffled := make([]string, len(endpoints))
+		for i, j := range rand.Perm(len(endpoints)) {
+			shuffled[i] = endpoints[j]
+		}
+		endpoints = shuffled
+	}
+	if len(endpoints) == 1 || !out.idempotent {
+		return c.post(ctx, endpoints[0], out)
+	}
+
+	exhausted := &EndpointsExhaustedError{}
+	for _, endpoint := range endpoints {
+		respBody, err := c.post(ctx, endpoint, out)
+		if err == nil {
+			return respBody, nil
+		}
+		if !canFailover(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		exhausted.Endpoints = append(exhausted.Endpoints, endpoint)
+		exhausted.Errors = append(exhausted.Errors, err)
+	}
+	return nil, exhausted
+}
+
+// canFailover reports whether a failure is specific to the endpoint that
+// produced it, so another endpoint may succeed.
+func canFailover(err error) bool {
+	if isEncodeError(err) {
+		return false
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var openErr *CircuitOpenError
+	if errors.As(err, &openErr) {
+		return true
+	}
+	var httpErr *HttpError
+	return errors.As(err, &httpErr) && httpErr.Status >= 500
+}
@@ -0,0 +1,114 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestFailoverToHealthyEndpoint(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer up.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoints: []string{"http://localhost:1", down.URL, up.URL},
+	})
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" {
+		t.Errorf("expected hash 'abc', got %q", envelope.Hash)
+	}
+}
+
+func TestFailoverAggregatesErrors(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer down.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoints:     []string{"http://localhost:1", down.URL},
+		EndpointOrder: trp.EndpointsRandom,
+	})
+	_, err := client.Resolve(context.Background(), testParams())
+	var exhausted *trp.EndpointsExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("expected EndpointsExhaustedError, got %T: %v", err, err)
+	}
+	if len(exhausted.Errors) != 2 {
+		t.Errorf("expected 2 endpoint failures, got %d", len(exhausted.Errors))
+	}
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) {
+		t.Error("expected errors.As to reach the HttpError cause")
+	}
+	var netErr *trp.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Error("expected errors.As to reach the NetworkError cause")
+	}
+}
+
+func TestFailoverStopsOnClientError(t *testing.T) {
+	hits := 0
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer bad.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoints: []string{bad.URL, bad.URL}})
+	_, err := client.Resolve(context.Background(), testParams())
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected HTTP 400, got %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected no failover on 4xx, got %d hits", hits)
+	}
+}
+
+func TestFailoverSwitchesEndpointCredentials(t *testing.T) {
+	var downAuth, upAuth, upRegion string
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downAuth = r.Header.Get("Authorization")
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upAuth, upRegion = r.Header.Get("Authorization"), r.Header.Get("X-Region")
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer up.Close()
+
+	client := trp.NewClient(trp.ClientOptions{
+		BearerToken: "global",
+		Headers:     map[string]string{"X-Region": "any"},
+		EndpointConfigs: []trp.EndpointConfig{
+			{URL: down.URL, BearerToken: "eu-key"},
+			{URL: up.URL, BasicAuth: &trp.BasicAuth{Username: "us", Password: "secret"}, Headers: map[string]string{"X-Region": "us"}},
+		},
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if downAuth != "Bearer eu-key" {
+		t.Errorf("expected the first endpoint's token, got %q", downAuth)
+	}
+	if upAuth != "Basic dXM6c2VjcmV0" || upRegion != "us" {
+		t.Errorf("expected the second endpoint's credentials and headers, got %q, %q", upAuth, upRegion)
+	}
+}
@@ -0,0 +1,14 @@
+package trp
+
+// Logger is the minimal logging interface used by Client. Any logger with a
+// printf-style Debugf method satisfies it.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// debugf logs through the configured Logger, if any.
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.options.Logger != nil {
+		c.options.Logger.Debugf(format, args...)
+	}
+}
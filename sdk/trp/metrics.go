@@ -0,0 +1,28 @@
+package trp
+
+import "time"
+
+// MetricsObserver receives timing and outcome data for TRP calls. Package
+// trpprom provides a Prometheus implementation.
+type MetricsObserver interface {
+	// ObserveResolve is invoked once per Resolve call with its total
+	// duration (retries included) and the returned error, if any.
+	ObserveResolve(duration time.Duration, err error)
+}
+
+// MethodObserver is a MetricsObserver that also buckets by JSON-RPC method.
+// When the configured observer implements it, ObserveCall is invoked once
+// per call of any method (Resolve, Submit, CheckStatus, Ping, ...; a
+// ResolveBatch is one call), retries included, and ObserveResolve is not
+// used. Failures before a request is built, such as an invalid TIR, are
+// then not observed.
+type MethodObserver interface {
+	MetricsObserver
+	ObserveCall(method string, duration time.Duration, err error)
+}
+
+// methodObserver returns the configured observer if it is a MethodObserver.
+func (c *Client) methodObserver() MethodObserver {
+	observer, _ := c.options.Metrics.(MethodObserver)
+	return observer
+}
@@ -0,0 +1,31 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Call is a JSON-RPC call as seen by middleware. Middleware may change
+// Method or Params before passing the call on; Params holds the typed value
+// the client method was given, e.g. ResolveParams for trp.resolve, with
+// client defaults such as env values already applied.
+type Call struct {
+	Method string
+	Params interface{}
+}
+
+// Handler executes a JSON-RPC call and returns its raw result.
+type Handler func(ctx context.Context, call *Call) (json.RawMessage, error)
+
+// Middleware wraps a Handler. It can observe or modify the call and its
+// outcome, or short-circuit by returning without invoking next.
+type Middleware func(next Handler) Handler
+
+// withMiddleware wraps h in the configured middleware, the first one
+// outermost.
+func (c *Client) withMiddleware(h Handler) Handler {
+	for i := len(c.options.Middleware) - 1; i >= 0; i-- {
+		h = c.options.Middleware[i](h)
+	}
+	return h
+}
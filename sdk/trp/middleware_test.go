@@ -0,0 +1,65 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestMiddlewareOrderAndRewrite(t *testing.T) {
+	var seen trp.ResolveParams
+	server := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		seen = params
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) trp.Middleware {
+		return func(next trp.Handler) trp.Handler {
+			return func(ctx context.Context, call *trp.Call) (json.RawMessage, error) {
+				order = append(order, name+">"+call.Method)
+				result, err := next(ctx, call)
+				order = append(order, "<"+name)
+				return result, err
+			}
+		}
+	}
+	tag := func(next trp.Handler) trp.Handler {
+		return func(ctx context.Context, call *trp.Call) (json.RawMessage, error) {
+			if params, ok := call.Params.(trp.ResolveParams); ok {
+				params.Env = map[string]interface{}{"tagged": true}
+				call.Params = params
+			}
+			return next(ctx, call)
+		}
+	}
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithMiddleware(trace("outer"), trace("inner"), tag))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := strings.Join(order, " "); got != "outer>trp.resolve inner>trp.resolve <inner <outer" {
+		t.Errorf("unexpected middleware order: %s", got)
+	}
+	if seen.Env["tagged"] != true {
+		t.Errorf("expected the rewritten params to be sent, got env %v", seen.Env)
+	}
+}
+
+func TestMiddlewareShortCircuits(t *testing.T) {
+	cached := func(next trp.Handler) trp.Handler {
+		return func(ctx context.Context, call *trp.Call) (json.RawMessage, error) {
+			return json.RawMessage(`{"hash":"cached","tx":"beef"}`), nil
+		}
+	}
+	client := trp.NewClientWithOptions("http://127.0.0.1:1", trp.WithMiddleware(cached))
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil || envelope.Hash != "cached" {
+		t.Fatalf("expected the middleware's result, got %+v (err %v)", envelope, err)
+	}
+}
@@ -0,0 +1,51 @@
+package trp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// isNotification reports whether r is an interim message rather than the
+// answer to a request: it carries no id and no error. (Error responses may
+// legitimately have a null id.)
+func (r *jsonRPCResponse) isNotification() bool {
+	return r.ID == "" && r.Error == nil
+}
+
+// notify passes a notification to the NotificationHandler, if any.
+func (c *Client) notify(r *jsonRPCResponse) {
+	if c.options.NotificationHandler != nil {
+		c.options.NotificationHandler(r.Method, r.Params)
+	}
+}
+
+// finalResponse decodes the response to a call from a body that may
+// contain notifications ahead of it, as several JSON values in a row. Each
+// notification is handed to the NotificationHandler; the first other
+// message is returned. An id-less message that ends the body is returned
+// too, so checkResponse reports the missing id.
+func (c *Client) finalResponse(body []byte) (*jsonRPCResponse, error) {
+	if c.codec() != JSONCodec {
+		var r jsonRPCResponse
+		if err := c.codec().Unmarshal(body, &r); err != nil {
+			return nil, &DeserializationError{Cause: err, Raw: string(body)}
+		}
+		return &r, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var r jsonRPCResponse
+		if err := dec.Decode(&r); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, &DeserializationError{Cause: err, Raw: string(body)}
+		}
+		if !r.isNotification() || !dec.More() {
+			return &r, nil
+		}
+		c.notify(&r)
+	}
+}
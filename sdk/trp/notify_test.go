@@ -0,0 +1,84 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestNotificationsBeforeResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","method":"trp.progress","params":{"stage":"selecting inputs"}}`)
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","id":null,"method":"trp.progress","params":{"stage":"balancing"}}`)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"hash":"abc","tx":"beef"}}`+"\n", id)
+	}))
+	defer server.Close()
+
+	var stages []string
+	client := trp.NewClientWithOptions(server.URL, trp.WithNotificationHandler(func(method string, params json.RawMessage) {
+		var p struct {
+			Stage string `json:"stage"`
+		}
+		json.Unmarshal(params, &p)
+		stages = append(stages, method+": "+p.Stage)
+	}))
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" {
+		t.Errorf("expected hash abc, got %q", envelope.Hash)
+	}
+	if len(stages) != 2 || stages[0] != "trp.progress: selecting inputs" || stages[1] != "trp.progress: balancing" {
+		t.Errorf("unexpected notifications: %q", stages)
+	}
+
+	if _, err := trp.NewClient(trp.ClientOptions{Endpoint: server.URL}).Resolve(context.Background(), testParams()); err != nil {
+		t.Errorf("expected notifications to be ignored without a handler, got %v", err)
+	}
+}
+
+func TestWebSocketNotifications(t *testing.T) {
+	url, _ := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		req, err := readWSRequest(ctx, conn)
+		if err != nil {
+			return
+		}
+		conn.Write(ctx, websocket.MessageText, []byte(`{"jsonrpc":"2.0","method":"trp.progress","params":{"stage":"balancing"}}`))
+		writeWSResult(ctx, conn, req.ID, "abc")
+		conn.Read(ctx)
+	})
+
+	var mu sync.Mutex
+	var methods []string
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{
+		NotificationHandler: func(method string, params json.RawMessage) {
+			mu.Lock()
+			methods = append(methods, method)
+			mu.Unlock()
+		},
+	})
+	defer client.Close()
+
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" {
+		t.Errorf("expected hash abc, got %q", envelope.Hash)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(methods) != 1 || methods[0] != "trp.progress" {
+		t.Errorf("unexpected notifications: %q", methods)
+	}
+}
@@ -0,0 +1,312 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Option configures a Client built by NewClientWithOptions.
+type Option func(*ClientOptions)
+
+// NewClientWithOptions creates a TRP client for the given endpoint, applying
+// each option in order. Later options override earlier ones.
+func NewClientWithOptions(endpoint string, opts ...Option) *Client {
+	options := ClientOptions{Endpoint: endpoint}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return newClient(options)
+}
+
+// WithTimeout sets the HTTP request timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *ClientOptions) { o.Timeout = timeout }
+}
+
+// WithRequestTimeout bounds each call as a whole, across retries.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *ClientOptions) { o.RequestTimeout = timeout }
+}
+
+// WithJSONRPCVersion overrides the JSON-RPC protocol version.
+func WithJSONRPCVersion(version string) Option {
+	return func(o *ClientOptions) { o.JSONRPCVersion = version }
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(o *ClientOptions) {
+		if o.Headers == nil {
+			o.Headers = map[string]string{}
+		}
+		o.Headers[key] = value
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *ClientOptions) { o.UserAgent = userAgent }
+}
+
+// WithEnvArg adds a default env value sent with every resolve.
+func WithEnvArg(key string, value interface{}) Option {
+	return func(o *ClientOptions) {
+		if o.EnvArgs == nil {
+			o.EnvArgs = map[string]interface{}{}
+		}
+		o.EnvArgs[key] = value
+	}
+}
+
+// WithArgsEncoder encodes resolve args with encoder instead of
+// json.Marshal; see ClientOptions.ArgsEncoder.
+func WithArgsEncoder(encoder func(args interface{}) (json.RawMessage, error)) Option {
+	return func(o *ClientOptions) { o.ArgsEncoder = encoder }
+}
+
+// WithEnvProvider computes fresh env values before every resolve.
+func WithEnvProvider(provider func(ctx context.Context) (map[string]interface{}, error)) Option {
+	return func(o *ClientOptions) { o.EnvProvider = provider }
+}
+
+// WithHTTPClient uses the given *http.Client for every request.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *ClientOptions) { o.HTTPClient = client }
+}
+
+// WithDialTimeout bounds establishing each TCP connection.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *ClientOptions) { o.DialTimeout = timeout }
+}
+
+// WithTLSHandshakeTimeout bounds the TLS handshake of each new connection;
+// see ClientOptions.TLSHandshakeTimeout.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(o *ClientOptions) { o.TLSHandshakeTimeout = timeout }
+}
+
+// WithConnectionPool sizes the pool of idle keep-alive connections; zero
+// values keep the defaults.
+func WithConnectionPool(maxIdle, maxIdlePerHost int, idleTimeout time.Duration) Option {
+	return func(o *ClientOptions) {
+		o.MaxIdleConns = maxIdle
+		o.MaxIdleConnsPerHost = maxIdlePerHost
+		o.IdleConnTimeout = idleTimeout
+	}
+}
+
+// WithoutKeepAlives opens a fresh connection for every request; see
+// ClientOptions.DisableKeepAlives.
+func WithoutKeepAlives() Option {
+	return func(o *ClientOptions) { o.DisableKeepAlives = true }
+}
+
+// WithMinTLSVersion rejects servers that cannot speak at least version.
+func WithMinTLSVersion(version uint16) Option {
+	return func(o *ClientOptions) { o.MinTLSVersion = version }
+}
+
+// WithHTTP2 speaks HTTP/2 to plain http:// endpoints as well as https://
+// ones; see ClientOptions.ForceHTTP2.
+func WithHTTP2() Option {
+	return func(o *ClientOptions) { o.ForceHTTP2 = true }
+}
+
+// WithPinnedCerts only trusts servers presenting a certificate with one of
+// the given hex SHA-256 fingerprints.
+func WithPinnedCerts(sha256Fingerprints ...string) Option {
+	return func(o *ClientOptions) { o.PinnedCertSHA256 = sha256Fingerprints }
+}
+
+// WithUnixSocket dials every connection to the Unix domain socket at path;
+// see ClientOptions.UnixSocket.
+func WithUnixSocket(path string) Option {
+	return func(o *ClientOptions) { o.UnixSocket = path }
+}
+
+// WithProxy routes every request through the proxy at proxyURL.
+func WithProxy(proxyURL string) Option {
+	return func(o *ClientOptions) { o.ProxyURL = proxyURL }
+}
+
+// WithRetry enables automatic retries of idempotent calls.
+func WithRetry(retry RetryOptions) Option {
+	return func(o *ClientOptions) { o.Retry = retry }
+}
+
+// WithBearerToken sends a static bearer token with every request.
+func WithBearerToken(token string) Option {
+	return func(o *ClientOptions) { o.BearerToken = token }
+}
+
+// WithBasicAuth authenticates every request with HTTP basic auth.
+func WithBasicAuth(username, password string) Option {
+	return func(o *ClientOptions) { o.BasicAuth = &BasicAuth{Username: username, Password: password} }
+}
+
+// WithTokenProvider fetches a fresh bearer token before every request.
+func WithTokenProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(o *ClientOptions) { o.TokenProvider = provider }
+}
+
+// WithSigner signs every request just before it is sent.
+func WithSigner(signer func(req *http.Request, body []byte) error) Option {
+	return func(o *ClientOptions) { o.Signer = signer }
+}
+
+// WithResolveMethod overrides the JSON-RPC method name used for resolution.
+func WithResolveMethod(method string) Option {
+	return func(o *ClientOptions) { o.ResolveMethod = method }
+}
+
+// WithFallbackMethod retries resolves with method when the server does not
+// know the primary one.
+func WithFallbackMethod(method string) Option {
+	return func(o *ClientOptions) { o.FallbackMethod = method }
+}
+
+// WithBatchSharedEnv sends env shared by ResolveBatch items only once, on
+// servers that support it.
+func WithBatchSharedEnv() Option {
+	return func(o *ClientOptions) { o.BatchSharedEnv = true }
+}
+
+// WithTirHash sends each TIR's hash for servers that cache compiled TIR;
+// see ClientOptions.SendTirHash.
+func WithTirHash() Option {
+	return func(o *ClientOptions) { o.SendTirHash = true }
+}
+
+// WithMaxBatchSize splits ResolveBatch calls into sub-batches of at most
+// size items; see ClientOptions.MaxBatchSize.
+func WithMaxBatchSize(size int) Option {
+	return func(o *ClientOptions) { o.MaxBatchSize = size }
+}
+
+// WithLenientEncoding tolerates resolved transactions mislabelled as base64.
+func WithLenientEncoding() Option {
+	return func(o *ClientOptions) { o.LenientEncoding = true }
+}
+
+// WithStrictDecoding rejects resolve results with unmodelled fields; see
+// ClientOptions.StrictDecoding.
+func WithStrictDecoding() Option {
+	return func(o *ClientOptions) { o.StrictDecoding = true }
+}
+
+// WithCompatibleTirVersions rejects TIRs of any other version client-side.
+func WithCompatibleTirVersions(versions ...string) Option {
+	return func(o *ClientOptions) { o.CompatibleTirVersions = versions }
+}
+
+// WithIDGenerator overrides how JSON-RPC request ids are produced.
+func WithIDGenerator(generator func() string) Option {
+	return func(o *ClientOptions) { o.IDGenerator = generator }
+}
+
+// WithResponseInterceptor observes the raw status and body of every response.
+func WithResponseInterceptor(interceptor func(status int, body []byte)) Option {
+	return func(o *ClientOptions) { o.ResponseInterceptor = interceptor }
+}
+
+// WithRequestRecorder observes the encoded body of every request.
+func WithRequestRecorder(recorder func(body []byte)) Option {
+	return func(o *ClientOptions) { o.RequestRecorder = recorder }
+}
+
+// WithNotificationHandler receives server notifications sent ahead of a
+// response.
+func WithNotificationHandler(handler func(method string, params json.RawMessage)) Option {
+	return func(o *ClientOptions) { o.NotificationHandler = handler }
+}
+
+// WithChunkedResults accepts resolve results streamed as NDJSON chunks.
+func WithChunkedResults() Option {
+	return func(o *ClientOptions) { o.ChunkedResults = true }
+}
+
+// WithErrorFormatter customizes the text of JSON-RPC errors.
+func WithErrorFormatter(formatter func(code int, message string, data interface{}) string) Option {
+	return func(o *ClientOptions) { o.ErrorFormatter = formatter }
+}
+
+// WithMiddleware appends middleware wrapping every JSON-RPC call.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(o *ClientOptions) { o.Middleware = append(o.Middleware, middleware...) }
+}
+
+// WithLogger enables debug logging of every HTTP round trip.
+func WithLogger(logger Logger) Option {
+	return func(o *ClientOptions) { o.Logger = logger }
+}
+
+// WithTracer wraps every JSON-RPC call in a trace span.
+func WithTracer(tracer Tracer) Option {
+	return func(o *ClientOptions) { o.Tracer = tracer }
+}
+
+// WithMetrics reports the duration and outcome of every Resolve.
+func WithMetrics(observer MetricsObserver) Option {
+	return func(o *ClientOptions) { o.Metrics = observer }
+}
+
+// WithCircuitBreaker guards each endpoint with a circuit breaker.
+func WithCircuitBreaker(options CircuitBreakerOptions) Option {
+	return func(o *ClientOptions) { o.CircuitBreaker = &options }
+}
+
+// WithValidateEnvelope rejects resolve results with an empty Tx or a
+// malformed Hash.
+func WithValidateEnvelope() Option {
+	return func(o *ClientOptions) { o.ValidateEnvelope = true }
+}
+
+// WithRateLimiter throttles every HTTP request through limiter.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(o *ClientOptions) { o.RateLimiter = limiter }
+}
+
+// WithMaxConcurrentRequests caps the HTTP requests in flight at once.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(o *ClientOptions) { o.MaxConcurrentRequests = n }
+}
+
+// WithMaxResponseBytes caps the size of response bodies.
+func WithMaxResponseBytes(limit int64) Option {
+	return func(o *ClientOptions) { o.MaxResponseBytes = limit }
+}
+
+// WithMaxRequestBytes caps the size of request bodies.
+func WithMaxRequestBytes(limit int64) Option {
+	return func(o *ClientOptions) { o.MaxRequestBytes = limit }
+}
+
+// WithRedirects follows up to max redirects (0 means the default of 10).
+func WithRedirects(max int) Option {
+	return func(o *ClientOptions) {
+		o.FollowRedirects = true
+		o.MaxRedirects = max
+	}
+}
+
+// WithCodec sets the wire encoding of requests and responses.
+func WithCodec(codec Codec) Option {
+	return func(o *ClientOptions) { o.Codec = codec }
+}
+
+// WithClock replaces the real clock, typically with a fake one in tests.
+func WithClock(clock Clock) Option {
+	return func(o *ClientOptions) { o.Clock = clock }
+}
+
+// WithStreamedRequests encodes large request bodies while sending them.
+func WithStreamedRequests() Option {
+	return func(o *ClientOptions) { o.StreamRequests = true }
+}
+
+// WithCompression gzip-compresses request bodies.
+func WithCompression() Option {
+	return func(o *ClientOptions) { o.Compression = true }
+}
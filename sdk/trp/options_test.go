@@ -0,0 +1,209 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	var receivedHeader string
+	var receivedEnv map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		receivedHeader = r.Header.Get("X-Api-Key")
+		var req struct {
+			Params struct {
+				Env map[string]interface{} `json:"env"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		receivedEnv = req.Params.Env
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithHeader("X-Api-Key", "secret"),
+		trp.WithEnvArg("network", "preprod"),
+		trp.WithEnvArg("fee", 10),
+	)
+
+	params := testParams()
+	params.Env = map[string]interface{}{"fee": 20}
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	if receivedHeader != "secret" {
+		t.Errorf("expected X-Api-Key header 'secret', got %q", receivedHeader)
+	}
+	if receivedEnv["network"] != "preprod" {
+		t.Errorf("expected client env arg to be sent, got %v", receivedEnv)
+	}
+	if receivedEnv["fee"] != float64(20) {
+		t.Errorf("expected request env to override client env, got %v", receivedEnv["fee"])
+	}
+	if _, ok := params.Env["network"]; ok {
+		t.Error("Resolve must not mutate the caller's env map")
+	}
+}
+
+func TestDial(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	if _, err := trp.Dial(srv.URL).Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+}
+
+func TestEnvProviderMergedPerRequest(t *testing.T) {
+	var receivedEnv map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req struct {
+			Params struct {
+				Env map[string]interface{} `json:"env"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		receivedEnv = req.Params.Env
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	slot := 100
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithEnvArg("network", "preprod"),
+		trp.WithEnvArg("slot", 0),
+		trp.WithEnvProvider(func(ctx context.Context) (map[string]interface{}, error) {
+			slot++
+			return map[string]interface{}{"slot": slot, "fee": 1}, nil
+		}),
+	)
+
+	params := testParams()
+	params.Env = map[string]interface{}{"fee": 2}
+	for _, want := range []float64{101, 102} {
+		if _, err := client.Resolve(context.Background(), params); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if receivedEnv["slot"] != want || receivedEnv["network"] != "preprod" || receivedEnv["fee"] != float64(2) {
+			t.Errorf("unexpected env %v (want slot %v)", receivedEnv, want)
+		}
+	}
+}
+
+func TestEnvProviderErrorAbortsRequest(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL,
+		trp.WithEnvProvider(func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, errors.New("tip unavailable")
+		}),
+	)
+	_, err := client.Resolve(context.Background(), testParams())
+	var envErr *trp.EnvProviderError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("expected EnvProviderError, got %T: %v", err, err)
+	}
+	if called {
+		t.Error("expected no request to reach the server")
+	}
+}
+
+func TestArgsEncoder(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	quantity, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	bigToString := func(args interface{}) (json.RawMessage, error) {
+		out := map[string]interface{}{}
+		for k, v := range args.(map[string]interface{}) {
+			if n, ok := v.(*big.Int); ok {
+				v = n.String()
+			}
+			out[k] = v
+		}
+		return json.Marshal(out)
+	}
+	client := trp.NewClientWithOptions(srv.URL, trp.WithArgsEncoder(bigToString))
+	params := testParams()
+	params.Args = map[string]interface{}{"quantity": quantity, "sender": "addr1"}
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	srv.Requests()[0].AssertArgs(t, map[string]interface{}{"quantity": "123456789012345678901234567890", "sender": "addr1"})
+	if params.Args["quantity"] != quantity {
+		t.Error("Resolve must not mutate the caller's args map")
+	}
+
+	failing := trp.NewClientWithOptions(srv.URL, trp.WithArgsEncoder(func(interface{}) (json.RawMessage, error) {
+		return json.RawMessage(`[1]`), nil
+	}))
+	if _, err := failing.Resolve(context.Background(), params); err == nil || len(srv.Requests()) != 1 {
+		t.Errorf("expected a non-object encoding to fail before sending, got %v", err)
+	}
+}
+
+func TestSendTirHash(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	params := testParams()
+	want, _ := params.Tir.ComputeHash()
+	for _, client := range []*trp.Client{trp.NewClient(trp.ClientOptions{Endpoint: srv.URL}), trp.NewClientWithOptions(srv.URL, trp.WithTirHash())} {
+		if _, err := client.Resolve(context.Background(), params); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+	}
+	requests := srv.Requests()
+	if hash := requests[0].Tir().Hash; hash != "" {
+		t.Errorf("expected no hash by default, got %q", hash)
+	}
+	if hash := requests[1].Tir().Hash; hash != want {
+		t.Errorf("expected hash %q, got %q", want, hash)
+	}
+	if params.Tir.Hash != "" {
+		t.Error("Resolve must not mutate the caller's params")
+	}
+}
+
+func TestLenientTirSentWithActualEncoding(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	hexTir := core.TirEnvelope{Content: "aabbcc", Encoding: core.EncodingHex, Version: testTir.Version}
+	mislabelled := hexTir
+	mislabelled.Encoding, mislabelled.Lenient = core.EncodingBase64, true
+	client := trp.NewClientWithOptions(srv.URL, trp.WithTirHash())
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: mislabelled}); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	sent := srv.Requests()[0].Tir()
+	want, _ := hexTir.ComputeHash()
+	if sent.Encoding != core.EncodingHex || sent.Hash != want {
+		t.Errorf("expected the TIR relabelled hex with the hash of its bytes, got %+v", sent)
+	}
+}
@@ -0,0 +1,37 @@
+package trp
+
+import "context"
+
+// RateLimiter throttles outgoing HTTP requests. *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+//
+// Wait blocks until a request may be sent and must return an error once ctx
+// is done.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// acquireSlot blocks until fewer than MaxConcurrentRequests requests are in
+// flight and returns the function that gives the slot back.
+func (c *Client) acquireSlot(ctx context.Context) (release func(), err error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return func() { <-c.slots }, nil
+	case <-ctx.Done():
+		return nil, &ConcurrencyLimitError{Limit: cap(c.slots), Cause: ctx.Err()}
+	}
+}
+
+// waitRateLimit blocks on the configured RateLimiter, if any.
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	if c.options.RateLimiter == nil {
+		return nil
+	}
+	if err := c.options.RateLimiter.Wait(ctx); err != nil {
+		return &RateLimitError{Cause: err}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+type countingLimiter struct {
+	waits int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return nil
+}
+
+// blockingLimiter never admits a request.
+type blockingLimiter struct{}
+
+func (blockingLimiter) Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRateLimiterWaitedPerAttempt(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+
+	limiter := &countingLimiter{}
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:    server.URL,
+		Retry:       fastRetry(2),
+		RateLimiter: limiter,
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&limiter.waits), atomic.LoadInt32(calls); got != want {
+		t.Errorf("expected %d limiter waits, got %d", want, got)
+	}
+}
+
+func TestRateLimiterRespectsCancellation(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": map[string]interface{}{}})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, RateLimiter: blockingLimiter{}})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Resolve(ctx, testParams())
+	var limitErr *trp.RateLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected RateLimitError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Error("expected no request to reach the server")
+	}
+}
@@ -0,0 +1,51 @@
+package trp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const defaultMaxRedirects = 10
+
+// checkRedirect implements the client's redirect policy for
+// http.Client.CheckRedirect.
+//
+// Only 307 and 308 redirects are followed: they replay the POST body,
+// whereas 301/302/303 would silently turn the JSON-RPC call into a GET.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !c.options.FollowRedirects {
+		return http.ErrUseLastResponse
+	}
+	if req.Response != nil {
+		switch req.Response.StatusCode {
+		case http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return http.ErrUseLastResponse
+		}
+	}
+	limit := c.options.MaxRedirects
+	if limit <= 0 {
+		limit = defaultMaxRedirects
+	}
+	if len(via) >= limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	if req.URL.Host != via[0].URL.Host {
+		// net/http keeps credentials for subdomains; be stricter and keep
+		// them on the original host only, custom headers included. The
+		// per-endpoint headers of every EndpointConfigs entry are dropped,
+		// not just the redirecting one's, so URL spelling cannot matter.
+		req.Header.Del("Authorization")
+		req.Header.Del("Proxy-Authorization")
+		req.Header.Del("Cookie")
+		for k := range c.options.Headers {
+			req.Header.Del(k)
+		}
+		for _, config := range c.options.EndpointConfigs {
+			for k := range config.Headers {
+				req.Header.Del(k)
+			}
+		}
+	}
+	return nil
+}
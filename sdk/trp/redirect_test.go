@@ -0,0 +1,131 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+// rpcServer answers every resolve successfully and records the headers of
+// the last request it received.
+func rpcServer(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		received = r.Header.Clone()
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func redirectTo(t *testing.T, target string, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target, status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedirectsNotFollowedByDefault(t *testing.T) {
+	target, _ := rpcServer(t)
+	redirector := redirectTo(t, target.URL, http.StatusTemporaryRedirect)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: redirector.URL})
+	_, err := client.Resolve(context.Background(), testParams())
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusTemporaryRedirect {
+		t.Fatalf("expected HttpError 307, got %T: %v", err, err)
+	}
+}
+
+func TestRedirectStripsCredentialsAcrossHosts(t *testing.T) {
+	target, received := rpcServer(t)
+	// 127.0.0.1 and localhost are different hosts for the same server.
+	crossHost := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	redirector := redirectTo(t, crossHost, http.StatusPermanentRedirect)
+
+	client := trp.NewClientWithOptions(redirector.URL,
+		trp.WithRedirects(3),
+		trp.WithBearerToken("secret"),
+		trp.WithHeader("X-Api-Key", "key"),
+	)
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := received.Get("Authorization"); got != "" {
+		t.Errorf("expected Authorization to be stripped, got %q", got)
+	}
+	if got := received.Get("X-Api-Key"); got != "" {
+		t.Errorf("expected X-Api-Key to be stripped, got %q", got)
+	}
+}
+
+func TestRedirectStripsEndpointConfigHeadersAcrossHosts(t *testing.T) {
+	target, received := rpcServer(t)
+	crossHost := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	redirector := redirectTo(t, crossHost, http.StatusTemporaryRedirect)
+
+	client := trp.NewClient(trp.ClientOptions{
+		EndpointConfigs: []trp.EndpointConfig{{
+			URL:         redirector.URL,
+			Headers:     map[string]string{"X-Api-Key": "endpoint-key"},
+			BearerToken: "endpoint-secret",
+		}},
+		FollowRedirects: true,
+	})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := received.Get("X-Api-Key"); got != "" {
+		t.Errorf("expected the endpoint's X-Api-Key to be stripped, got %q", got)
+	}
+	if got := received.Get("Authorization"); got != "" {
+		t.Errorf("expected the endpoint's Authorization to be stripped, got %q", got)
+	}
+}
+
+func TestRedirectKeepsCredentialsOnSameHost(t *testing.T) {
+	var received string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		received = r.Header.Get("Authorization")
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL+"/old", trp.WithRedirects(0), trp.WithBearerToken("secret"))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if received != "Bearer secret" {
+		t.Errorf("expected Authorization on same-host redirect, got %q", received)
+	}
+}
+
+func TestRedirectLimit(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithRedirects(2))
+	_, err := client.Resolve(context.Background(), testParams())
+	if err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Fatalf("expected redirect limit error, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package trp
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// RequestBuilder assembles ResolveParams:
+//
+//	params := trp.NewRequest().
+//	    WithTir(tir).
+//	    WithArgs(map[string]interface{}{"quantity": 100}).
+//	    WithEnv(core.EnvMap{core.EnvKeyNetwork: core.Preprod}).
+//	    Build()
+//
+// Env set here travels on the request and overrides, key by key, the
+// client-level EnvArgs and EnvProvider values.
+type RequestBuilder struct {
+	params ResolveParams
+}
+
+// NewRequest returns an empty RequestBuilder.
+func NewRequest() *RequestBuilder {
+	return &RequestBuilder{}
+}
+
+// WithTir sets the transaction template to resolve.
+func (b *RequestBuilder) WithTir(tir core.TirEnvelope) *RequestBuilder {
+	b.params.Tir = tir
+	return b
+}
+
+// WithArgs adds transaction arguments, replacing any already set under the
+// same names.
+func (b *RequestBuilder) WithArgs(args map[string]interface{}) *RequestBuilder {
+	if b.params.Args == nil {
+		b.params.Args = make(map[string]interface{}, len(args))
+	}
+	maps.Copy(b.params.Args, args)
+	return b
+}
+
+// WithPositionalArgs sets the arguments of a TIR compiled with positional
+// parameters, in order; see core.PositionalArgs. They are sent in place of
+// any WithArgs values.
+func (b *RequestBuilder) WithPositionalArgs(args core.ArgList) *RequestBuilder {
+	b.params.PositionalArgs = args
+	return b
+}
+
+// WithEnv adds per-request env values, replacing any already set under the
+// same keys.
+func (b *RequestBuilder) WithEnv(env core.EnvMap) *RequestBuilder {
+	if b.params.Env == nil {
+		b.params.Env = make(map[string]interface{}, len(env))
+	}
+	maps.Copy(b.params.Env, env)
+	return b
+}
+
+// Build returns the assembled ResolveParams. The maps are copies, so the
+// builder can keep being used for further requests.
+func (b *RequestBuilder) Build() ResolveParams {
+	params := b.params
+	params.Args = maps.Clone(b.params.Args)
+	params.Env = maps.Clone(b.params.Env)
+	params.PositionalArgs = slices.Clone(b.params.PositionalArgs)
+	return params
+}
+
+// EstimateSize returns the size in bytes of the JSON-RPC trp.resolve
+// request carrying p, as checked against ClientOptions.MaxRequestBytes. It
+// does not account for env a client merges in or for compression.
+func (p ResolveParams) EstimateSize() (int, error) {
+	body, err := json.Marshal(jsonRPCRequest{JSONRPC: "2.0", ID: uuid.Nil.String(), Method: "trp.resolve", Params: p})
+	if err != nil {
+		return 0, err
+	}
+	return len(body), nil
+}
@@ -0,0 +1,106 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	builder := trp.NewRequest().
+		WithTir(testTir).
+		WithArgs(map[string]interface{}{"quantity": 100}).
+		WithEnv(core.EnvMap{"network": "preprod"})
+	params := builder.Build()
+	builder.WithEnv(core.EnvMap{"network": "mainnet"})
+	if params.Env["network"] != "preprod" {
+		t.Errorf("expected Build to copy the env, got %v", params.Env)
+	}
+
+	client := trp.NewClientWithOptions(srv.URL,
+		trp.WithEnvArg("network", "preview"),
+		trp.WithEnvArg("fee_policy", "standard"),
+	)
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	req := srv.Requests()[0]
+	req.AssertArgs(t, map[string]interface{}{"quantity": 100})
+	env := req.Env()
+	if env["network"] != "preprod" || env["fee_policy"] != "standard" {
+		t.Errorf("expected request env to override client env per key, got %v", env)
+	}
+	if req.Tir().Content != testTir.Content {
+		t.Errorf("expected the builder's TIR to be sent, got %+v", req.Tir())
+	}
+}
+
+func TestPositionalArgs(t *testing.T) {
+	var received trp.ResolveParams
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		received = params
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	params := trp.NewRequest().
+		WithTir(testTir).
+		WithPositionalArgs(core.PositionalArgs("addr_test1", 100, []interface{}{1, 2})).
+		Build()
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	sent, _ := json.Marshal(srv.Requests()[0].PositionalArgs())
+	if string(sent) != `["addr_test1",100,[1,2]]` {
+		t.Errorf("expected args sent as an array in order, got %s", sent)
+	}
+	if received.Args != nil || len(received.PositionalArgs) != 3 {
+		t.Errorf("expected the server to decode positional args, got %+v", received)
+	}
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	params := trp.NewRequest().WithTir(testTir).WithArgs(map[string]interface{}{"quantity": 100}).Build()
+	size, err := params.EstimateSize()
+	if err != nil {
+		t.Fatalf("EstimateSize failed: %v", err)
+	}
+	var sent int
+	client := trp.NewClientWithOptions(srv.URL, trp.WithRequestRecorder(func(body []byte) { sent = len(body) }))
+	if _, err := client.Resolve(context.Background(), params); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if sent != size {
+		t.Errorf("expected EstimateSize %d to match the %d bytes sent", size, sent)
+	}
+
+	client = trp.NewClientWithOptions(srv.URL, trp.WithMaxRequestBytes(int64(size-1)))
+	var tooLarge *trp.RequestTooLargeError
+	if _, err := client.Resolve(context.Background(), params); !errors.As(err, &tooLarge) || tooLarge.Size != int64(size) {
+		t.Errorf("expected RequestTooLargeError for %d bytes, got %v", size, err)
+	}
+	if _, _, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{params, params}); !errors.As(err, &tooLarge) {
+		t.Errorf("expected RequestTooLargeError for the batch, got %v", err)
+	}
+	if got := len(srv.Requests()); got != 1 {
+		t.Errorf("expected the oversized requests not to be sent, server saw %d", got)
+	}
+}
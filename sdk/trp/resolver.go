@@ -0,0 +1,15 @@
+package trp
+
+import "context"
+
+// Resolver resolves transactions. It is satisfied by *Client and
+// *WebSocketClient, so application code can depend on it and substitute a
+// fake in unit tests.
+type Resolver interface {
+	Resolve(ctx context.Context, params ResolveParams, opts ...CallOption) (*TxEnvelope, error)
+}
+
+var (
+	_ Resolver = (*Client)(nil)
+	_ Resolver = (*WebSocketClient)(nil)
+)
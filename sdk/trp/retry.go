@@ -0,0 +1,88 @@
+package trp
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// RetryOptions configures automatic retries of TRP calls (trp.resolve,
+// trp.submit and trp.checkStatus).
+//
+// By default only transport failures and HTTP 502/503/504 responses are
+// retried. JSON-RPC application errors and other HTTP statuses fail
+// immediately.
+type RetryOptions struct {
+	MaxRetries     int           // Retries after the first attempt (0 disables retries)
+	InitialBackoff time.Duration // Delay before the first retry (default: 200ms)
+	MaxBackoff     time.Duration // Upper bound for any single delay (default: 5s)
+
+	// RetryIf, when set, replaces the default rules: a failed attempt is
+	// retried when it returns true. attempt is the zero-based number of the
+	// attempt that failed. MaxRetries and the context deadline still bound
+	// the retries, and calls that are not idempotent are never retried.
+	RetryIf func(err error, attempt int) bool
+}
+
+// shouldRetry applies RetryIf, or the default rules when it is unset.
+func (r RetryOptions) shouldRetry(err error, attempt int) bool {
+	if r.RetryIf != nil {
+		return r.RetryIf(err, attempt)
+	}
+	return isRetryable(err)
+}
+
+// backoff returns the jittered delay to wait after the given failed attempt
+// (zero-based). The nominal delay doubles each attempt up to MaxBackoff; the
+// actual delay is drawn uniformly from [nominal/2, nominal].
+func (r RetryOptions) backoff(attempt int) time.Duration {
+	initial := r.InitialBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	limit := r.MaxBackoff
+	if limit <= 0 {
+		limit = defaultMaxBackoff
+	}
+
+	delay := initial
+	for i := 0; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)+1))
+}
+
+// isRetryable reports whether a failed attempt may succeed if repeated.
+func isRetryable(err error) bool {
+	var pinErr *CertificatePinError
+	if isEncodeError(err) || errors.As(err, &pinErr) {
+		return false
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var closedErr *ConnectionClosedError
+	if errors.As(err, &closedErr) {
+		return true
+	}
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		switch httpErr.Status {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+// flakyServer fails the first `failures` requests with the given HTTP status
+// and answers a successful resolve afterwards.
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		if atomic.AddInt32(&calls, 1) <= failures {
+			http.Error(w, "unavailable", status)
+			return
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func fastRetry(maxRetries int) trp.RetryOptions {
+	return trp.RetryOptions{
+		MaxRetries:     maxRetries,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+}
+
+func TestRetryRecoversFromServiceUnavailable(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: fastRetry(3)})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusBadGateway)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: fastRetry(2)})
+	_, err := client.Resolve(context.Background(), testParams())
+	var httpErr *trp.HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HttpError, got %T: %v", err, err)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusBadRequest)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: fastRetry(3)})
+	if _, err := client.Resolve(context.Background(), testParams()); err == nil {
+		t.Fatal("expected error for HTTP 400")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestRetryAppliesToSubmit(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: fastRetry(3)})
+	resp, err := client.Submit(context.Background(), trp.SubmitParams{Tx: core.NewHexEnvelope("beef")})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if resp.Hash != "abc" {
+		t.Errorf("expected hash 'abc', got %q", resp.Hash)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusServiceUnavailable)
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Retry:    trp.RetryOptions{MaxRetries: 5, InitialBackoff: time.Second, MaxBackoff: time.Second},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.Resolve(ctx, testParams()); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("retry loop overran the context deadline: %s", elapsed)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestRetryIfOverridesDefaultRules(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			trptest.WriteError(w, id, &trptest.Error{Code: -32099, Message: "overloaded"})
+			return
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	var attempts []int
+	retry := fastRetry(3)
+	retry.RetryIf = func(err error, attempt int) bool {
+		attempts = append(attempts, attempt)
+		var rpcErr *trp.GenericRpcError
+		return errors.As(err, &rpcErr) && rpcErr.Code == -32099
+	}
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, Retry: retry})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Errorf("expected RetryIf to see attempts [0 1], got %v", attempts)
+	}
+
+	// MaxRetries still bounds a predicate that retries everything, even a
+	// status the default rules give up on.
+	failing, failingCalls := flakyServer(t, 10, http.StatusBadRequest)
+	retry.RetryIf = func(error, int) bool { return true }
+	client = trp.NewClient(trp.ClientOptions{Endpoint: failing.URL, Retry: retry})
+	if _, err := client.Resolve(context.Background(), testParams()); err == nil {
+		t.Fatal("expected the call to fail once MaxRetries is exhausted")
+	}
+	if got := atomic.LoadInt32(failingCalls); got != 4 {
+		t.Errorf("expected 4 attempts, got %d", got)
+	}
+}
@@ -0,0 +1,72 @@
+package trp
+
+import (
+	"context"
+	"sync"
+)
+
+// inflight tracks the calls running on a Client so Shutdown can cancel them
+// and wait for them to return.
+type inflight struct {
+	mu       sync.Mutex
+	shutdown bool
+	calls    sync.WaitGroup
+	ctx      context.Context    // Parent of every call; cancelled by Shutdown
+	cancel   context.CancelFunc // Cancels ctx
+}
+
+func newInflight() *inflight {
+	f := &inflight{}
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	return f
+}
+
+// Shutdown marks the client closed, cancels every call in flight and waits
+// for them to return, or for ctx to expire, whichever comes first. Cancelled
+// calls fail with an error wrapping context.Canceled; calls made after
+// Shutdown fail with *ClientClosedError. Idle connections are released as
+// by Close.
+//
+// It returns ctx.Err() if ctx expires before every call has returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	f := c.inflight
+	f.mu.Lock()
+	f.shutdown = true
+	f.mu.Unlock()
+	c.Close()
+	f.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		f.calls.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enter registers a call with the client, returning a context that is also
+// cancelled by Shutdown. The returned func must be called when the call
+// returns.
+func (c *Client) enter(ctx context.Context) (context.Context, func(), error) {
+	f := c.inflight
+	f.mu.Lock()
+	if f.shutdown {
+		f.mu.Unlock()
+		return nil, nil, &ClientClosedError{}
+	}
+	f.calls.Add(1)
+	f.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(f.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		f.calls.Done()
+	}, nil
+}
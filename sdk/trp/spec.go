@@ -2,20 +2,95 @@
 package trp
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"github.com/tx3-lang/go-sdk/sdk/core"
 )
 
 // ResolveParams is the request body for the trp.resolve JSON-RPC method.
 type ResolveParams struct {
-	Tir  core.TirEnvelope       `json:"tir"`
-	Args map[string]interface{} `json:"args"`
-	Env  map[string]interface{} `json:"env,omitempty"`
+	Tir     core.TirEnvelope       `json:"tir"`
+	Args    map[string]interface{} `json:"args"`
+	Env     map[string]interface{} `json:"env,omitempty"` // Per-request env; overrides the client's EnvArgs and EnvProvider per key
+	Options *ResolveOptions        `json:"options,omitempty"`
+	Meta    map[string]string      `json:"meta,omitempty"` // Correlation labels for server-side logs; see WithLabels
+
+	// PositionalArgs, when non-nil, is sent as the "args" array in place of
+	// Args, for TIRs compiled with positional parameters. See
+	// core.PositionalArgs.
+	PositionalArgs core.ArgList `json:"-"`
+}
+
+// resolveParams has ResolveParams' fields without its methods.
+type resolveParams ResolveParams
+
+// MarshalJSON encodes args as an array when PositionalArgs is set.
+func (p ResolveParams) MarshalJSON() ([]byte, error) {
+	if p.PositionalArgs == nil {
+		return json.Marshal(resolveParams(p))
+	}
+	return json.Marshal(struct {
+		resolveParams
+		Args core.ArgList `json:"args"`
+	}{resolveParams(p), p.PositionalArgs})
+}
+
+// UnmarshalJSON decodes an "args" array into PositionalArgs and an object
+// into Args.
+func (p *ResolveParams) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*resolveParams
+		Args json.RawMessage `json:"args"`
+	}{resolveParams: (*resolveParams)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	args := bytes.TrimSpace(aux.Args)
+	switch {
+	case len(args) == 0 || bytes.Equal(args, []byte("null")):
+		return nil
+	case args[0] == '[':
+		return json.Unmarshal(args, &p.PositionalArgs)
+	default:
+		return json.Unmarshal(args, &p.Args)
+	}
+}
+
+// ResolveOptions are optional server-side resolution flags.
+type ResolveOptions struct {
+	ValidateOnly bool `json:"validateOnly,omitempty"` // Validate without producing a tx
+}
+
+// withValidateOnly returns a copy of o with ValidateOnly set, leaving the
+// caller's value untouched.
+func (o *ResolveOptions) withValidateOnly() *ResolveOptions {
+	var out ResolveOptions
+	if o != nil {
+		out = *o
+	}
+	out.ValidateOnly = true
+	return &out
 }
 
 // TxEnvelope is the response from trp.resolve, containing the resolved transaction.
 type TxEnvelope struct {
-	Hash string `json:"hash"` // Transaction hash (hex)
-	Tx   string `json:"tx"`   // CBOR transaction bytes (hex)
+	Hash     string `json:"hash"`               // Transaction hash (hex)
+	Tx       string `json:"tx"`                 // CBOR transaction bytes (hex, unless Encoding says otherwise)
+	Encoding string `json:"encoding,omitempty"` // Optional encoding of Tx ("hex" or "base64")
+
+	// Fee and ExecutionUnits are the transaction fee (in lovelace) and the
+	// total script budget, when the server reports them; nil otherwise.
+	Fee            *uint64         `json:"fee,omitempty"`
+	ExecutionUnits *ExecutionUnits `json:"executionUnits,omitempty"`
+
+	Lenient bool `json:"-"` // Bytes retries mislabelled base64 as hex; see ClientOptions.LenientEncoding
+}
+
+// ExecutionUnits is a Plutus script execution budget.
+type ExecutionUnits struct {
+	Mem   uint64 `json:"mem"`
+	Steps uint64 `json:"steps"`
 }
 
 // SubmitParams is the request body for the trp.submit JSON-RPC method.
@@ -0,0 +1,144 @@
+package trp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// StreamResult is the outcome of one resolution in a ResolveStream. Index is
+// the zero-based position of Args in the input stream.
+type StreamResult struct {
+	Index int
+	Args  map[string]interface{}
+	ResolveResult
+}
+
+// ResolveStream resolves tir once per arg set received from args, running up
+// to concurrency resolutions at a time (values below 1 mean 1). Results are
+// delivered as they complete, so they may arrive out of input order; use
+// Index or Args to correlate them.
+//
+// The returned channel is closed once args is closed and every pending
+// resolution has been delivered, or once ctx is done. Callers must drain it
+// or cancel ctx.
+func (c *Client) ResolveStream(ctx context.Context, tir core.TirEnvelope, args <-chan map[string]interface{}, concurrency int, opts ...CallOption) <-chan StreamResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type job struct {
+		index int
+		args  map[string]interface{}
+	}
+	jobs := make(chan job)
+	results := make(chan StreamResult)
+
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case <-ctx.Done():
+				return
+			case a, ok := <-args:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- job{index: index, args: a}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				envelope, err := c.Resolve(ctx, ResolveParams{Tir: tir, Args: j.args}, opts...)
+				res := StreamResult{Index: j.index, Args: j.args, ResolveResult: ResolveResult{Envelope: envelope, Err: err}}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// ResolveAll resolves every element of params, running up to concurrency
+// resolutions at a time (values below 1 mean 1), and returns the envelopes
+// index-aligned with params.
+//
+// With failFast, the first failure cancels the resolutions still running or
+// pending and is returned, wrapped with the index of its item, alongside
+// the envelopes resolved so far. Otherwise every item is attempted: failed
+// items are left nil and their errors, each wrapped with its index, are
+// joined into the returned error.
+func (c *Client) ResolveAll(ctx context.Context, params []ResolveParams, concurrency int, failFast bool, opts ...CallOption) ([]*TxEnvelope, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	envelopes := make([]*TxEnvelope, len(params))
+	errs := make([]error, len(params))
+	var (
+		first     error
+		firstOnce sync.Once
+		wg        sync.WaitGroup
+	)
+	slots := make(chan struct{}, concurrency)
+	for i := range params {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// Not started: the caller's context is done, or failFast
+			// stopped the run.
+			for j := i; j < len(params); j++ {
+				errs[j] = fmt.Errorf("resolve item %d: %w", j, ctx.Err())
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			envelope, err := c.Resolve(ctx, params[i], opts...)
+			if err != nil {
+				errs[i] = fmt.Errorf("resolve item %d: %w", i, err)
+				if failFast {
+					firstOnce.Do(func() {
+						first = errs[i]
+						cancel()
+					})
+				}
+				return
+			}
+			envelopes[i] = envelope
+		}(i)
+	}
+	wg.Wait()
+
+	if first != nil {
+		return envelopes, first
+	}
+	return envelopes, errors.Join(errs...)
+}
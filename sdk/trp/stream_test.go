@@ -0,0 +1,129 @@
+package trp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestResolveStream(t *testing.T) {
+	var inFlight, peak int32
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if params.Tir.Content != testTir.Content {
+			return nil, fmt.Errorf("unexpected TIR %q", params.Tir.Content)
+		}
+		return &trp.TxEnvelope{Hash: fmt.Sprint(params.Args["recipient"]), Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	args := make(chan map[string]interface{})
+	go func() {
+		defer close(args)
+		for i := 0; i < 20; i++ {
+			args <- map[string]interface{}{"recipient": fmt.Sprintf("addr%d", i)}
+		}
+	}()
+
+	seen := make(map[int]bool)
+	for res := range client.ResolveStream(context.Background(), testTir, args, 4) {
+		if res.Err != nil {
+			t.Fatalf("item %d failed: %v", res.Index, res.Err)
+		}
+		if want := fmt.Sprintf("addr%d", res.Index); res.Envelope.Hash != want || res.Args["recipient"] != want {
+			t.Errorf("item %d: result %q does not match input %v", res.Index, res.Envelope.Hash, res.Args)
+		}
+		seen[res.Index] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("expected 20 results, got %d", len(seen))
+	}
+	if p := atomic.LoadInt32(&peak); p > 4 {
+		t.Errorf("concurrency exceeded the limit: peak %d", p)
+	}
+}
+
+func TestResolveStreamStopsOnCancel(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	args := make(chan map[string]interface{}) // never closed
+	results := client.ResolveStream(ctx, testTir, args, 2)
+	args <- map[string]interface{}{}
+	<-results
+	cancel()
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("result channel not closed after cancellation")
+		}
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	var calls atomic.Int32
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		calls.Add(1)
+		if params.Args["fail"] == true {
+			return nil, &trptest.Error{Code: trptest.CodeServerError, Message: "cannot resolve"}
+		}
+		return &trp.TxEnvelope{Hash: fmt.Sprint(params.Args["n"]), Tx: "beef"}, nil
+	})
+	defer srv.Close()
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+
+	params := make([]trp.ResolveParams, 6)
+	for i := range params {
+		params[i] = trp.ResolveParams{Tir: testTir, Args: map[string]interface{}{"n": i, "fail": i == 1 || i == 4}}
+	}
+
+	envelopes, err := client.ResolveAll(context.Background(), params, 3, false)
+	if err == nil || !strings.Contains(err.Error(), "resolve item 1:") || !strings.Contains(err.Error(), "resolve item 4:") {
+		t.Errorf("expected both failures to be reported, got %v", err)
+	}
+	for i, envelope := range envelopes {
+		failed := i == 1 || i == 4
+		if failed != (envelope == nil) || (!failed && envelope.Hash != fmt.Sprint(i)) {
+			t.Errorf("item %d: unexpected envelope %+v", i, envelope)
+		}
+	}
+
+	calls.Store(0)
+	envelopes, err = client.ResolveAll(context.Background(), params, 1, true)
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) || !strings.HasPrefix(err.Error(), "resolve item 1:") {
+		t.Errorf("expected the first failure, got %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected fail-fast to stop after the failure, server saw %d calls", got)
+	}
+	if len(envelopes) != len(params) || envelopes[0] == nil || envelopes[2] != nil {
+		t.Errorf("expected envelopes resolved before the failure only, got %v", envelopes)
+	}
+}
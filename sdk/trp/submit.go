@@ -0,0 +1,65 @@
+package trp
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// Stages of ResolveAndSubmit, reported in StageError.Stage.
+const (
+	StageResolve = "resolve"
+	StageSign    = "sign"
+	StageSubmit  = "submit"
+)
+
+// ResolveAndSubmit resolves params, hands the resulting transaction to sign
+// and submits the signed transaction it returns, yielding the hash of the
+// submitted transaction. sign is expected to return the complete signed
+// transaction, witnesses included; it is not called if the resolve fails.
+//
+// Any failure is returned as *StageError naming the step that failed, with
+// the underlying error available through errors.As. opts apply to both the
+// resolve and the submit call, except that WithValidateOnly and the
+// Idempotency-Key are dropped from the resolve: the submit needs a real
+// transaction, and a server deduplicating by key must not answer the
+// submit with the resolve's result.
+func (c *Client) ResolveAndSubmit(ctx context.Context, params ResolveParams, sign func(TxEnvelope) (TxEnvelope, error), opts ...CallOption) (string, error) {
+	envelope, err := c.Resolve(ctx, params, append(opts[:len(opts):len(opts)], forResolveStep)...)
+	if err != nil {
+		return "", &StageError{Stage: StageResolve, Cause: err}
+	}
+	if envelope == nil {
+		return "", &StageError{Stage: StageResolve, Cause: &InvalidEnvelopeError{Field: "tx", Reason: "is missing"}}
+	}
+	signed, err := sign(*envelope)
+	if err != nil {
+		return "", &StageError{Stage: StageSign, Cause: err}
+	}
+	tx, err := signed.Bytes()
+	if err != nil {
+		return "", &StageError{Stage: StageSign, Cause: &DeserializationError{Cause: err, Raw: signed.Tx}}
+	}
+	resp, err := c.Submit(ctx, SubmitParams{Tx: core.NewHexEnvelope(hex.EncodeToString(tx))}, opts...)
+	if err != nil {
+		return "", &StageError{Stage: StageSubmit, Cause: err}
+	}
+	if resp.Hash == "" {
+		return signed.Hash, nil
+	}
+	return resp.Hash, nil
+}
+
+// forResolveStep strips the options of ResolveAndSubmit that only make sense
+// for its submit.
+func forResolveStep(o *callOptions) {
+	o.validateOnly = false
+	o.idempotencyKey = ""
+	for k := range o.headers {
+		if http.CanonicalHeaderKey(k) == "Idempotency-Key" {
+			delete(o.headers, k)
+		}
+	}
+}
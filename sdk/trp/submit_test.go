@@ -0,0 +1,95 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestResolveAndSubmit(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.resolve": map[string]interface{}{"hash": "abc", "tx": "beef"},
+		"trp.submit":  map[string]interface{}{"hash": "abc"},
+	})
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	var signed string
+	hash, err := client.ResolveAndSubmit(context.Background(), testParams(), func(tx trp.TxEnvelope) (trp.TxEnvelope, error) {
+		signed = tx.Tx
+		tx.Tx += "00"
+		return tx, nil
+	})
+	if err != nil {
+		t.Fatalf("ResolveAndSubmit failed: %v", err)
+	}
+	if hash != "abc" || signed != "beef" {
+		t.Errorf("unexpected hash %q or signed tx %q", hash, signed)
+	}
+}
+
+func TestResolveAndSubmitReportsStage(t *testing.T) {
+	server := trptest.NewMethodServer(map[string]interface{}{
+		"trp.resolve": map[string]interface{}{"hash": "abc", "tx": "beef"},
+	})
+	defer server.Close()
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	ok := func(tx trp.TxEnvelope) (trp.TxEnvelope, error) { return tx, nil }
+	refused := errors.New("user declined")
+
+	for _, tc := range []struct {
+		stage string
+		sign  func(trp.TxEnvelope) (trp.TxEnvelope, error)
+		cause func(error) bool
+	}{
+		{trp.StageSign, func(trp.TxEnvelope) (trp.TxEnvelope, error) { return trp.TxEnvelope{}, refused }, func(err error) bool { return errors.Is(err, refused) }},
+		{trp.StageSubmit, ok, trp.IsMethodNotFound},
+	} {
+		_, err := client.ResolveAndSubmit(context.Background(), testParams(), tc.sign)
+		var stageErr *trp.StageError
+		if !errors.As(err, &stageErr) || stageErr.Stage != tc.stage || !tc.cause(err) {
+			t.Errorf("expected a %s StageError, got %T: %v", tc.stage, err, err)
+		}
+	}
+}
+
+func TestResolveAndSubmitResolveStepOptions(t *testing.T) {
+	keys := map[string]string{}
+	var validateOnly bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Options *trp.ResolveOptions `json:"options"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		keys[req.Method] = r.Header.Get("Idempotency-Key")
+		if req.Method == "trp.resolve" {
+			validateOnly = req.Params.Options != nil && req.Params.Options.ValidateOnly
+		}
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, err := client.ResolveAndSubmit(context.Background(), testParams(),
+		func(tx trp.TxEnvelope) (trp.TxEnvelope, error) { return tx, nil },
+		trp.WithValidateOnly(), trp.WithIdempotencyKey("submit-1"))
+	if err != nil {
+		t.Fatalf("ResolveAndSubmit failed: %v", err)
+	}
+	if validateOnly {
+		t.Error("expected the resolve step not to be a dry run")
+	}
+	if keys["trp.resolve"] != "" || keys["trp.submit"] != "submit-1" {
+		t.Errorf("expected the Idempotency-Key on the submit only, got %v", keys)
+	}
+}
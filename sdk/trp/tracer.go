@@ -0,0 +1,59 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Tracer instruments TRP calls with trace spans. Package trpotel provides an
+// OpenTelemetry implementation; the trp package itself has no tracing
+// dependency.
+type Tracer interface {
+	// StartCall is invoked before a JSON-RPC call is sent (retries included).
+	// The returned context is used for the call, and finish is invoked
+	// exactly once with the outcome.
+	StartCall(ctx context.Context, call CallInfo) (context.Context, func(CallResult))
+}
+
+// CallInfo describes an outgoing JSON-RPC call.
+type CallInfo struct {
+	Method    string // JSON-RPC method, e.g. "trp.resolve"
+	Endpoint  string // Primary TRP endpoint URL
+	RequestID string // JSON-RPC request id (or a batch label)
+}
+
+// CallResult describes the outcome of a JSON-RPC call.
+type CallResult struct {
+	TxHash string // Transaction hash reported in the result, if any
+	Err    error
+}
+
+// startCall opens a span through the configured Tracer and times the call
+// for a MethodObserver. The returned finish function is always safe to call.
+func (c *Client) startCall(ctx context.Context, out outgoing) (context.Context, func(json.RawMessage, error)) {
+	observer := c.methodObserver()
+	if c.options.Tracer == nil && observer == nil {
+		return ctx, func(json.RawMessage, error) {}
+	}
+	start := c.clock().Now()
+	end := func(CallResult) {}
+	if c.options.Tracer != nil {
+		ctx, end = c.options.Tracer.StartCall(ctx, CallInfo{
+			Method:    out.method,
+			Endpoint:  c.endpoints()[0],
+			RequestID: out.id,
+		})
+	}
+	return ctx, func(result json.RawMessage, err error) {
+		if observer != nil {
+			observer.ObserveCall(out.method, c.since(start), err)
+		}
+		var hashed struct {
+			Hash string `json:"hash"`
+		}
+		if result != nil {
+			json.Unmarshal(result, &hashed)
+		}
+		end(CallResult{TxHash: hashed.Hash, Err: err})
+	}
+}
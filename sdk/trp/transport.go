@@ -0,0 +1,252 @@
+package trp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxySchemes are the proxy URL schemes net/http can dial through.
+var proxySchemes = map[string]bool{"http": true, "https": true, "socks5": true, "socks5h": true}
+
+// Validate reports the first invalid setting in o, as *InvalidOptionsError.
+// NewClient cannot fail, so a client built from invalid options returns the
+// same error from every call instead; Validate lets callers catch it at
+// startup.
+func (o ClientOptions) Validate() error {
+	if _, err := parseProxyURL(o.ProxyURL); err != nil {
+		return &InvalidOptionsError{Option: "ProxyURL", Cause: err}
+	}
+	if o.UnixSocket != "" && o.ProxyURL != "" {
+		return &InvalidOptionsError{Option: "UnixSocket", Cause: errors.New("cannot be combined with ProxyURL")}
+	}
+	switch o.MinTLSVersion {
+	case 0, tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+	default:
+		return &InvalidOptionsError{Option: "MinTLSVersion", Cause: fmt.Errorf("unknown TLS version 0x%04x", o.MinTLSVersion)}
+	}
+	for _, pin := range o.PinnedCertSHA256 {
+		if b, err := hex.DecodeString(pin); err != nil || len(b) != sha256.Size {
+			return &InvalidOptionsError{Option: "PinnedCertSHA256", Cause: fmt.Errorf("%q is not a hex SHA-256 digest", pin)}
+		}
+	}
+	for _, limit := range []struct {
+		option string
+		value  int64
+	}{
+		{"MaxIdleConns", int64(o.MaxIdleConns)},
+		{"MaxIdleConnsPerHost", int64(o.MaxIdleConnsPerHost)},
+		{"IdleConnTimeout", int64(o.IdleConnTimeout)},
+		{"MaxConcurrentRequests", int64(o.MaxConcurrentRequests)},
+	} {
+		if limit.value < 0 {
+			return &InvalidOptionsError{Option: limit.option, Cause: errors.New("must not be negative")}
+		}
+	}
+	return o.validateEndpoints()
+}
+
+// endpointSchemes are the URL schemes a TRP endpoint may use; ws and wss
+// are for WebSocketClient.
+var endpointSchemes = map[string]bool{"http": true, "https": true, "ws": true, "wss": true}
+
+// validateEndpoints checks that the endpoints the client would call are
+// absolute URLs. As in newClient, EndpointConfigs replaces Endpoints, which
+// replaces Endpoint.
+func (o ClientOptions) validateEndpoints() error {
+	option, endpoints := "Endpoint", []string{o.Endpoint}
+	switch {
+	case len(o.EndpointConfigs) > 0:
+		option, endpoints = "EndpointConfigs", make([]string, len(o.EndpointConfigs))
+		for i, config := range o.EndpointConfigs {
+			endpoints[i] = config.URL
+		}
+	case len(o.Endpoints) > 0:
+		option, endpoints = "Endpoints", o.Endpoints
+	}
+	for _, endpoint := range endpoints {
+		if err := checkEndpoint(endpoint); err != nil {
+			return &InvalidOptionsError{Option: option, Cause: err}
+		}
+	}
+	return nil
+}
+
+func checkEndpoint(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return errors.New("must not be empty")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if !endpointSchemes[u.Scheme] || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute http or https URL", raw)
+	}
+	return nil
+}
+
+// parseProxyURL parses a ProxyURL setting; an empty one yields nil.
+func parseProxyURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !proxySchemes[u.Scheme] {
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https, socks5 or socks5h)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// newTransport builds the transport for a client that owns its
+// http.Client, or returns nil when the options need nothing beyond
+// http.DefaultTransport. Errors are *InvalidOptionsError.
+func newTransport(o ClientOptions) (http.RoundTripper, error) {
+	proxy, err := parseProxyURL(o.ProxyURL)
+	if err != nil {
+		return nil, &InvalidOptionsError{Option: "ProxyURL", Cause: err}
+	}
+	if proxy == nil && o.UnixSocket == "" && o.DialTimeout <= 0 && o.TLSHandshakeTimeout <= 0 &&
+		o.MaxIdleConns <= 0 && o.MaxIdleConnsPerHost <= 0 && o.IdleConnTimeout <= 0 && !o.DisableKeepAlives &&
+		o.MinTLSVersion == 0 && len(o.PinnedCertSHA256) == 0 && !o.ForceHTTP2 {
+		return nil, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if o.MinTLSVersion != 0 || len(o.PinnedCertSHA256) > 0 {
+		transport.TLSClientConfig = &tls.Config{MinVersion: o.MinTLSVersion}
+		if len(o.PinnedCertSHA256) > 0 {
+			transport.TLSClientConfig.VerifyConnection = verifyPinnedCert(o.PinnedCertSHA256)
+		}
+	}
+	if o.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = o.TLSHandshakeTimeout
+	}
+	if o.MaxIdleConns > 0 {
+		transport.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = o.IdleConnTimeout
+	}
+	transport.DisableKeepAlives = o.DisableKeepAlives
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if o.DialTimeout > 0 || o.UnixSocket != "" {
+		// Same timeout and keep-alive as http.DefaultTransport's dialer,
+		// unless overridden.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if o.DialTimeout > 0 {
+			dialer.Timeout = o.DialTimeout
+		}
+		transport.DialContext = dialer.DialContext
+		if socket := o.UnixSocket; socket != "" {
+			transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			}
+		}
+	}
+	if o.ForceHTTP2 {
+		// Over TLS, ALPN picks HTTP/2 or falls back to HTTP/1.1. Cleartext
+		// has no such negotiation, so http:// endpoints get a transport of
+		// their own that speaks HTTP/2 with prior knowledge.
+		transport.ForceAttemptHTTP2 = true
+		plain := transport.Clone()
+		plain.Protocols = new(http.Protocols)
+		plain.Protocols.SetUnencryptedHTTP2(true)
+		return &http2Transport{tls: transport, plain: plain}, nil
+	}
+	return transport, nil
+}
+
+// http2Transport routes http:// requests to an HTTP/2-only transport and
+// everything else to the negotiating one.
+type http2Transport struct {
+	tls, plain *http.Transport
+}
+
+func (t *http2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return t.plain.RoundTrip(req)
+	}
+	return t.tls.RoundTrip(req)
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach both
+// pools.
+func (t *http2Transport) CloseIdleConnections() {
+	t.tls.CloseIdleConnections()
+	t.plain.CloseIdleConnections()
+}
+
+// verifyPinnedCert accepts a TLS connection only if one of the certificates
+// the server presented has a SHA-256 fingerprint among pins. It runs after
+// the usual chain verification, not instead of it.
+func verifyPinnedCert(pins []string) func(tls.ConnectionState) error {
+	allowed := make(map[string]bool, len(pins))
+	for _, pin := range pins {
+		allowed[strings.ToLower(pin)] = true
+	}
+	return func(state tls.ConnectionState) error {
+		presented := make([]string, len(state.PeerCertificates))
+		for i, cert := range state.PeerCertificates {
+			sum := sha256.Sum256(cert.Raw)
+			presented[i] = hex.EncodeToString(sum[:])
+			if allowed[presented[i]] {
+				return nil
+			}
+		}
+		return &CertificatePinError{Host: state.ServerName, Presented: presented}
+	}
+}
+
+// remoteAddr records, through httptrace, the address an HTTP request was
+// connected to, or the last one dialled if no connection was made. Dials
+// may race (e.g. IPv4 and IPv6 attempts), hence the lock.
+type remoteAddr struct {
+	mu        sync.Mutex
+	connected string
+	dialled   string
+}
+
+func (r *remoteAddr) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		ConnectStart: func(_, addr string) {
+			r.mu.Lock()
+			r.dialled = addr
+			r.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			r.mu.Lock()
+			r.connected = info.Conn.RemoteAddr().String()
+			r.mu.Unlock()
+		},
+	}
+}
+
+func (r *remoteAddr) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.connected != "" {
+		return r.connected
+	}
+	return r.dialled
+}
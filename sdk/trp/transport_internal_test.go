@@ -0,0 +1,30 @@
+package trp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifyPinnedCert(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	cert := server.Certificate()
+	sum := sha256.Sum256(cert.Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+	state := tls.ConnectionState{ServerName: "trp.example", PeerCertificates: []*x509.Certificate{cert}}
+
+	if err := verifyPinnedCert([]string{strings.ToUpper(fingerprint)})(state); err != nil {
+		t.Errorf("expected the pinned certificate to be accepted, got %v", err)
+	}
+	err := verifyPinnedCert([]string{strings.Repeat("00", sha256.Size)})(state)
+	var pinErr *CertificatePinError
+	if !errors.As(err, &pinErr) || pinErr.Host != "trp.example" || len(pinErr.Presented) != 1 || pinErr.Presented[0] != fingerprint {
+		t.Errorf("expected CertificatePinError listing the presented fingerprint, got %v", err)
+	}
+}
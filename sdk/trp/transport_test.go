@@ -0,0 +1,287 @@
+package trp_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestProxyURL(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		id := trptest.RequestID(r)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":{"hash":"abc","tx":"beef"}}`))
+	}))
+	defer proxy.Close()
+
+	client := trp.NewClientWithOptions("http://trp.example.invalid/rpc", trp.WithProxy(proxy.URL))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve through proxy failed: %v", err)
+	}
+	if proxiedURL != "http://trp.example.invalid/rpc" {
+		t.Errorf("expected the proxy to receive the TRP URL, got %q", proxiedURL)
+	}
+}
+
+func TestInvalidProxyURL(t *testing.T) {
+	for _, raw := range []string{"ftp://proxy:21", "http://", "://nope"} {
+		options := trp.ClientOptions{Endpoint: "http://localhost:1", ProxyURL: raw}
+		var invalid *trp.InvalidOptionsError
+		if err := options.Validate(); !errors.As(err, &invalid) || invalid.Option != "ProxyURL" {
+			t.Errorf("%q: expected InvalidOptionsError for ProxyURL, got %v", raw, err)
+		}
+		if _, err := trp.NewClient(options).Resolve(context.Background(), testParams()); !errors.As(err, &invalid) {
+			t.Errorf("%q: expected calls to fail with InvalidOptionsError, got %T: %v", raw, err, err)
+		}
+	}
+
+	if err := (trp.ClientOptions{Endpoint: "http://localhost:1", ProxyURL: "socks5://127.0.0.1:1080"}).Validate(); err != nil {
+		t.Errorf("expected socks5 proxies to be accepted, got %v", err)
+	}
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	for _, options := range []trp.ClientOptions{
+		{},
+		{Endpoint: "localhost:3000"},
+		{Endpoint: "ftp://trp.example.com"},
+		{Endpoints: []string{"http://a.example.com", "%zz"}},
+		{EndpointConfigs: []trp.EndpointConfig{{URL: " "}}},
+		// EndpointConfigs replaces Endpoints, so only its URLs count.
+		{Endpoints: []string{"http://a.example.com"}, EndpointConfigs: []trp.EndpointConfig{{URL: "%zz"}}},
+	} {
+		var invalid *trp.InvalidOptionsError
+		if err := options.Validate(); !errors.As(err, &invalid) || !strings.HasPrefix(invalid.Option, "Endpoint") {
+			t.Errorf("%+v: expected InvalidOptionsError for the endpoint, got %v", options, err)
+		}
+		if _, err := trp.NewClient(options).Resolve(context.Background(), testParams()); !errors.As(err, &invalid) {
+			t.Errorf("%+v: expected calls to fail with InvalidOptionsError, got %T: %v", options, err, err)
+		}
+	}
+
+	valid := trp.ClientOptions{Endpoints: []string{"%zz"}, EndpointConfigs: []trp.EndpointConfig{{URL: "http://a.example.com"}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected EndpointConfigs to replace invalid Endpoints, got %v", err)
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "trp.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	var path string
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := trp.NewClientWithOptions("http://localhost/rpc", trp.WithUnixSocket(socket))
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve over the socket failed: %v", err)
+	}
+	if path != "/rpc" {
+		t.Errorf("expected the endpoint path to be sent, got %q", path)
+	}
+
+	var invalid *trp.InvalidOptionsError
+	if err := (trp.ClientOptions{UnixSocket: socket, ProxyURL: "http://proxy:3128"}).Validate(); !errors.As(err, &invalid) || invalid.Option != "UnixSocket" {
+		t.Errorf("expected InvalidOptionsError for UnixSocket, got %v", err)
+	}
+}
+
+func TestDialTimeout(t *testing.T) {
+	// 10.255.255.1 is non-routable: connecting hangs until the dialer gives up.
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:    "http://10.255.255.1:81",
+		Timeout:     10 * time.Second,
+		DialTimeout: 50 * time.Millisecond,
+	})
+	start := time.Now()
+	_, err := client.Resolve(context.Background(), testParams())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !trp.IsTimeout(err) {
+		t.Skipf("connect failed without hanging on this network: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the dial timeout to abandon the host quickly, took %s", elapsed)
+	}
+}
+
+func TestTLSHandshakeTimeout(t *testing.T) {
+	// A listener that accepts connections but never answers the handshake.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:            "https://" + listener.Addr().String(),
+		Timeout:             10 * time.Second,
+		TLSHandshakeTimeout: 50 * time.Millisecond,
+	})
+	start := time.Now()
+	_, err = client.Resolve(context.Background(), testParams())
+	if err == nil || !strings.Contains(err.Error(), "TLS handshake timeout") {
+		t.Fatalf("expected a TLS handshake timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the handshake to be abandoned quickly, took %s", elapsed)
+	}
+}
+
+func TestIdleConnTimeout(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name   string
+		option trp.Option
+		want   int32
+	}{
+		{"default pool", trp.WithConnectionPool(0, 0, 0), 1},                    // the connection is reused
+		{"1ms idle timeout", trp.WithConnectionPool(0, 0, time.Millisecond), 2}, // it expires between calls
+		{"no keep-alives", trp.WithoutKeepAlives(), 2},                          // it is closed after each call
+	} {
+		conns.Store(0)
+		client := trp.NewClientWithOptions(server.URL, tc.option)
+		for i := 0; i < 2; i++ {
+			if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		client.Close()
+		if got := conns.Load(); got != tc.want {
+			t.Errorf("%s: expected %d connections, got %d", tc.name, tc.want, got)
+		}
+	}
+
+	var invalid *trp.InvalidOptionsError
+	if err := (trp.ClientOptions{MaxIdleConnsPerHost: -1}).Validate(); !errors.As(err, &invalid) || invalid.Option != "MaxIdleConnsPerHost" {
+		t.Errorf("expected InvalidOptionsError for MaxIdleConnsPerHost, got %v", err)
+	}
+}
+
+func TestMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	client := trp.NewClientWithOptions(server.URL, trp.WithMinTLSVersion(tls.VersionTLS13))
+	_, err := client.Resolve(context.Background(), testParams())
+	if err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Errorf("expected the TLS 1.2 server to be refused, got %v", err)
+	}
+
+	var invalid *trp.InvalidOptionsError
+	for option, options := range map[string]trp.ClientOptions{
+		"MinTLSVersion":    {MinTLSVersion: 0x0399},
+		"PinnedCertSHA256": {PinnedCertSHA256: []string{"abc"}},
+	} {
+		if err := options.Validate(); !errors.As(err, &invalid) || invalid.Option != option {
+			t.Errorf("expected InvalidOptionsError for %s, got %v", option, err)
+		}
+	}
+}
+
+func TestForceHTTP2(t *testing.T) {
+	var proto atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto.Store(int32(r.ProtoMajor))
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	for _, tc := range []struct {
+		options []trp.Option
+		want    int32
+	}{
+		{nil, 1},
+		{[]trp.Option{trp.WithHTTP2()}, 2},
+	} {
+		client := trp.NewClientWithOptions(server.URL, tc.options...)
+		if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		client.Close()
+		if got := proto.Load(); got != tc.want {
+			t.Errorf("expected HTTP/%d, got HTTP/%d", tc.want, got)
+		}
+	}
+}
+
+func TestRemoteAddrReported(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+	}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	_, meta, err := client.ResolveDetailed(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("ResolveDetailed failed: %v", err)
+	}
+	if meta.RemoteAddr != addr {
+		t.Errorf("expected metadata RemoteAddr %q, got %q", addr, meta.RemoteAddr)
+	}
+
+	fail.Store(true)
+	var httpErr *trp.HttpError
+	if _, err := client.Resolve(context.Background(), testParams()); !errors.As(err, &httpErr) || httpErr.RemoteAddr != addr {
+		t.Errorf("expected HttpError from %q, got %v", addr, err)
+	}
+
+	var netErr *trp.NetworkError
+	refused := trp.NewClient(trp.ClientOptions{Endpoint: "http://127.0.0.1:1"})
+	if _, err := refused.Resolve(context.Background(), testParams()); !errors.As(err, &netErr) || netErr.RemoteAddr != "127.0.0.1:1" {
+		t.Errorf("expected NetworkError naming the attempted address, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/tx3-lang/go-sdk/sdk/trp/trpotel
+
+go 1.24.2
+
+require (
+	github.com/tx3-lang/go-sdk/sdk v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.37.0
+	go.opentelemetry.io/otel/sdk v1.37.0
+	go.opentelemetry.io/otel/trace v1.37.0
+)
+
+require (
+	github.com/coder/websocket v1.8.13 // indirect
+	github.com/fxamacker/cbor/v2 v2.7.0 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/metric v1.37.0 // indirect
+	golang.org/x/crypto v0.37.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+)
+
+replace github.com/tx3-lang/go-sdk/sdk => ../..
@@ -0,0 +1,90 @@
+// Package trpotel provides OpenTelemetry tracing for the TRP client.
+//
+// It lives in its own module so applications that do not trace never
+// compile the OpenTelemetry dependency:
+//
+//	client := trp.NewClient(trp.ClientOptions{
+//	    Endpoint: "https://trp.example",
+//	    Tracer:   trpotel.NewTracer(),
+//	})
+package trpotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+// instrumentationName identifies the spans produced by this package.
+const instrumentationName = "github.com/tx3-lang/go-sdk/sdk/trp"
+
+// Span attribute keys recorded on every TRP span.
+const (
+	AttrEndpoint  = attribute.Key("trp.endpoint")
+	AttrMethod    = attribute.Key("rpc.method")
+	AttrRequestID = attribute.Key("rpc.jsonrpc.request_id")
+	AttrTxHash    = attribute.Key("tx3.tx.hash")
+)
+
+// Option configures the tracer returned by NewTracer.
+type Option func(*tracer)
+
+// WithTracerProvider pins the TracerProvider used to create spans instead of
+// resolving it per call.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *tracer) { t.provider = provider }
+}
+
+// NewTracer returns a trp.Tracer that records one client span per JSON-RPC
+// call, named after the method (e.g. "trp.resolve").
+//
+// Unless WithTracerProvider is given, spans are created from the provider of
+// the span already in the call's context, falling back to the global
+// provider. With no provider configured anywhere this is a no-op.
+func NewTracer(opts ...Option) trp.Tracer {
+	t := &tracer{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+type tracer struct {
+	provider trace.TracerProvider
+}
+
+func (t *tracer) StartCall(ctx context.Context, call trp.CallInfo) (context.Context, func(trp.CallResult)) {
+	ctx, span := t.providerFor(ctx).Tracer(instrumentationName).Start(ctx, call.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrEndpoint.String(call.Endpoint),
+			AttrMethod.String(call.Method),
+			AttrRequestID.String(call.RequestID),
+		),
+	)
+	return ctx, func(result trp.CallResult) {
+		if result.TxHash != "" {
+			span.SetAttributes(AttrTxHash.String(result.TxHash))
+		}
+		if result.Err != nil {
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		}
+		span.End()
+	}
+}
+
+func (t *tracer) providerFor(ctx context.Context) trace.TracerProvider {
+	if t.provider != nil {
+		return t.provider
+	}
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		return span.TracerProvider()
+	}
+	return otel.GetTracerProvider()
+}
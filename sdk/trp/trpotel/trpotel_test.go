@@ -0,0 +1,90 @@
+package trpotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trpotel"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestResolveRecordsSpan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": "abc123", "tx": "beef"})
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint:    server.URL,
+		Tracer:      trpotel.NewTracer(trpotel.WithTracerProvider(provider)),
+		IDGenerator: func() string { return "req-1" },
+	})
+	_, err := client.Resolve(context.Background(), trp.ResolveParams{
+		Tir: core.TirEnvelope{Content: "aabb", Encoding: "hex", Version: "v1beta0"},
+	})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "trp.resolve" {
+		t.Errorf("expected span name 'trp.resolve', got %q", span.Name())
+	}
+	attrs := map[string]string{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	want := map[string]string{
+		string(trpotel.AttrEndpoint):  server.URL,
+		string(trpotel.AttrMethod):    "trp.resolve",
+		string(trpotel.AttrRequestID): "req-1",
+		string(trpotel.AttrTxHash):    "abc123",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("attribute %s: expected %q, got %q", k, v, attrs[k])
+		}
+	}
+}
+
+func TestFailedCallRecordsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := trp.NewClient(trp.ClientOptions{
+		Endpoint: server.URL,
+		Tracer:   trpotel.NewTracer(trpotel.WithTracerProvider(provider)),
+	})
+	if _, err := client.CheckStatus(context.Background(), []string{"abc"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", spans[0].Status())
+	}
+}
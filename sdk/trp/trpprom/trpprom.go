@@ -0,0 +1,82 @@
+// Package trpprom provides a Prometheus implementation of trp.MetricsObserver.
+//
+// It lives in its own module so applications that do not export metrics
+// never compile the Prometheus dependency:
+//
+//	observer, err := trpprom.NewObserver(prometheus.DefaultRegisterer)
+//	if err != nil { /* ... */ }
+//	client := trp.NewClient(trp.ClientOptions{
+//	    Endpoint: "https://trp.example",
+//	    Metrics:  observer,
+//	})
+package trpprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+// Observer records TRP call counts, error counts, and latencies, labelled
+// by JSON-RPC method ("method").
+//
+// Exposed metrics:
+//   - trp_client_requests_total
+//   - trp_client_errors_total
+//   - trp_client_request_duration_seconds
+type Observer struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+var _ trp.MethodObserver = (*Observer)(nil)
+
+// NewObserver creates an Observer and registers its collectors with reg.
+func NewObserver(reg prometheus.Registerer) (*Observer, error) {
+	labels := []string{"method"}
+	o := &Observer{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trp",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Total number of TRP calls.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trp",
+			Subsystem: "client",
+			Name:      "errors_total",
+			Help:      "Total number of TRP calls that returned an error.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "trp",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of TRP calls, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+	for _, c := range []prometheus.Collector{o.requests, o.errors, o.latency} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// ObserveCall implements trp.MethodObserver.
+func (o *Observer) ObserveCall(method string, duration time.Duration, err error) {
+	o.requests.WithLabelValues(method).Inc()
+	if err != nil {
+		o.errors.WithLabelValues(method).Inc()
+	}
+	o.latency.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveResolve implements trp.MetricsObserver. The client calls
+// ObserveCall instead; ObserveResolve records under method "trp.resolve".
+func (o *Observer) ObserveResolve(duration time.Duration, err error) {
+	o.ObserveCall("trp.resolve", duration, err)
+}
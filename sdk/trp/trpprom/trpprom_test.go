@@ -0,0 +1,68 @@
+package trpprom_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp/trpprom"
+)
+
+func TestObserverCounts(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	observer, err := trpprom.NewObserver(reg)
+	if err != nil {
+		t.Fatalf("NewObserver failed: %v", err)
+	}
+
+	observer.ObserveCall("trp.resolve", 10*time.Millisecond, nil)
+	observer.ObserveCall("trp.resolve", 20*time.Millisecond, errors.New("boom"))
+	observer.ObserveCall("trp.submit", 5*time.Millisecond, nil)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	// Values of the trp.resolve series.
+	values := map[string]float64{}
+	methods := map[string]bool{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			method := m.GetLabel()[0].GetValue()
+			methods[method] = true
+			if method != "trp.resolve" {
+				continue
+			}
+			switch {
+			case m.Counter != nil:
+				values[f.GetName()] = m.Counter.GetValue()
+			case m.Histogram != nil:
+				values[f.GetName()] = float64(m.Histogram.GetSampleCount())
+			}
+		}
+	}
+	if !methods["trp.submit"] {
+		t.Errorf("expected a trp.submit series, got methods %v", methods)
+	}
+	if values["trp_client_requests_total"] != 2 {
+		t.Errorf("expected 2 requests, got %v", values["trp_client_requests_total"])
+	}
+	if values["trp_client_errors_total"] != 1 {
+		t.Errorf("expected 1 error, got %v", values["trp_client_errors_total"])
+	}
+	if values["trp_client_request_duration_seconds"] != 2 {
+		t.Errorf("expected 2 latency samples, got %v", values["trp_client_request_duration_seconds"])
+	}
+}
+
+func TestObserverDoubleRegistrationFails(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := trpprom.NewObserver(reg); err != nil {
+		t.Fatalf("NewObserver failed: %v", err)
+	}
+	if _, err := trpprom.NewObserver(reg); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+}
@@ -0,0 +1,88 @@
+package trptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+)
+
+// CapturedRequest is a trp.resolve call as the Server received it. Its
+// accessors decode the params on demand; numbers in Args and Env are
+// json.Number, so large amounts keep their exact value.
+type CapturedRequest struct {
+	Method string
+	Params json.RawMessage // Raw JSON-RPC params
+}
+
+// capturedParams mirrors trp.ResolveParams with exact numbers.
+type capturedParams struct {
+	Tir  core.TirEnvelope       `json:"tir"`
+	Args interface{}            `json:"args"` // An object, or an array of positional args
+	Env  map[string]interface{} `json:"env"`
+}
+
+func (c *CapturedRequest) decode() capturedParams {
+	var p capturedParams
+	dec := json.NewDecoder(bytes.NewReader(c.Params))
+	dec.UseNumber()
+	dec.Decode(&p)
+	return p
+}
+
+// Tir returns the TIR envelope the client sent.
+func (c *CapturedRequest) Tir() core.TirEnvelope { return c.decode().Tir }
+
+// Args returns the transaction arguments the client sent, or nil if they
+// were positional.
+func (c *CapturedRequest) Args() map[string]interface{} {
+	args, _ := c.decode().Args.(map[string]interface{})
+	return args
+}
+
+// PositionalArgs returns the positional arguments the client sent, or nil
+// if they were named.
+func (c *CapturedRequest) PositionalArgs() []interface{} {
+	args, _ := c.decode().Args.([]interface{})
+	return args
+}
+
+// Env returns the env the client sent, after merging its defaults.
+func (c *CapturedRequest) Env() map[string]interface{} { return c.decode().Env }
+
+// AssertArgs fails t unless the client sent exactly the expected args.
+// Values are compared by their JSON encoding, so an int in expected matches
+// the number the client serialised.
+func (c *CapturedRequest) AssertArgs(t testing.TB, expected map[string]interface{}) {
+	t.Helper()
+	want, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("AssertArgs: cannot encode expected args: %v", err)
+	}
+	var normalized map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(want))
+	dec.UseNumber()
+	dec.Decode(&normalized)
+
+	got := c.Args()
+	if !reflect.DeepEqual(got, normalized) {
+		sent, _ := json.Marshal(got)
+		t.Errorf("unexpected args:\n got: %s\nwant: %s", sent, want)
+	}
+}
+
+// Requests returns the trp.resolve calls received so far, in arrival order,
+// including those answered with an injected error.
+func (s *Server) Requests() []CapturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]CapturedRequest(nil), s.captured...)
+}
+
+func (s *Server) capture(req request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.captured = append(s.captured, CapturedRequest{Method: req.Method, Params: req.Params})
+}
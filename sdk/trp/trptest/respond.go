@@ -0,0 +1,97 @@
+package trptest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// RequestID returns the raw JSON-RPC id of r so hand-written handlers can
+// echo it, decompressing a gzipped body first. r.Body is restored as it was
+// received for the handler to decode.
+func RequestID(r *http.Request) json.RawMessage {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		if zr, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			body, _ = io.ReadAll(zr)
+		}
+	}
+	var req struct {
+		ID json.RawMessage `json:"id"`
+	}
+	json.Unmarshal(body, &req)
+	return req.ID
+}
+
+// WriteResult answers the JSON-RPC request with the given id with result.
+func WriteResult(w http.ResponseWriter, id json.RawMessage, result interface{}) {
+	writeResponse(w, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// WriteError answers the JSON-RPC request with the given id with e.
+func WriteError(w http.ResponseWriter, id json.RawMessage, e *Error) {
+	writeResponse(w, response{JSONRPC: "2.0", ID: id, Error: toWire(e)})
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// NewMethodServer starts a server answering each JSON-RPC method from
+// results, echoing the request id. A *Error value is sent as the error
+// response; methods missing from results get CodeMethodNotFound. The caller
+// must Close the server.
+func NewMethodServer(results map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		json.NewDecoder(r.Body).Decode(&req)
+		result, ok := results[req.Method]
+		if !ok {
+			WriteError(w, req.ID, &Error{Code: CodeMethodNotFound, Message: "method not found"})
+			return
+		}
+		if e, ok := result.(*Error); ok {
+			WriteError(w, req.ID, e)
+			return
+		}
+		WriteResult(w, req.ID, result)
+	}))
+}
+
+// NewReplayServer starts a server that answers successive requests with
+// recorded response bodies, in order, such as ones captured through
+// trp.ClientOptions.ResponseInterceptor or loaded from golden files. The id
+// in each recorded response is replaced with the id of the request it
+// answers. Requests beyond the recording are answered with CodeServerError.
+// The caller must Close the server.
+func NewReplayServer(responses ...[]byte) *httptest.Server {
+	var mu sync.Mutex
+	next := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := RequestID(r)
+		mu.Lock()
+		i := next
+		next++
+		mu.Unlock()
+		if i >= len(responses) {
+			WriteError(w, id, &Error{Code: CodeServerError, Message: fmt.Sprintf("no recorded response for request %d", i+1)})
+			return
+		}
+
+		body := responses[i]
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) == nil && fields != nil {
+			fields["id"] = id
+			body, _ = json.Marshal(fields)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+}
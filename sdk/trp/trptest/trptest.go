@@ -0,0 +1,197 @@
+// Package trptest provides an in-process TRP JSON-RPC server for tests.
+//
+// It decodes incoming trp.resolve requests (single or batched), hands the
+// params to a user-supplied handler, and encodes a proper JSON-RPC response:
+//
+//	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+//	    return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+//	})
+//	defer srv.Close()
+//
+//	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+//
+// Requests returns the calls the Server received, so tests can assert on
+// what the client sent:
+//
+//	srv.Requests()[0].AssertArgs(t, map[string]interface{}{"quantity": 100})
+//
+// NewMethodServer answers arbitrary methods from a fixed table. Tests that
+// need full control of the HTTP exchange can write their own handler and
+// answer with RequestID, WriteResult and WriteError.
+//
+// For golden testing, record the client's requests with
+// trp.WithRequestRecorder (and fixed ids from trp.WithIDGenerator) and
+// compare them against files; record the server's answers with
+// trp.WithResponseInterceptor and replay them with NewReplayServer:
+//
+//	srv := trptest.NewReplayServer(golden("resolve.response.json"))
+//	defer srv.Close()
+//
+//	var sent []byte
+//	client := trp.NewClientWithOptions(srv.URL,
+//	    trp.WithIDGenerator(func() string { return "1" }),
+//	    trp.WithRequestRecorder(func(body []byte) { sent = body }),
+//	)
+//	client.Resolve(ctx, params)
+//	// compare sent with golden("resolve.request.json")
+package trptest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+// Standard JSON-RPC error codes used by the server.
+const (
+	CodeParseError     = trp.ErrCodeParseError
+	CodeMethodNotFound = trp.ErrCodeMethodNotFound
+	CodeServerError    = trp.ErrCodeServerError
+)
+
+// ResolveHandler answers a single trp.resolve call.
+type ResolveHandler func(params trp.ResolveParams) (*trp.TxEnvelope, error)
+
+// Error is a JSON-RPC error object. A handler returning *Error (or an error
+// wrapping one) has it sent verbatim; any other error is sent with
+// CodeServerError and the error's message.
+type Error struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// Server is an httptest.Server speaking the TRP JSON-RPC protocol.
+type Server struct {
+	*httptest.Server
+
+	handler ResolveHandler
+
+	mu       sync.Mutex
+	injected []*Error
+	captured []CapturedRequest
+}
+
+// NewServer starts a Server that answers trp.resolve calls with handler.
+// Other methods are answered with CodeMethodNotFound. The caller must Close
+// the server.
+func NewServer(handler ResolveHandler) *Server {
+	s := &Server{handler: handler}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// InjectError queues a canned error: the next resolve call is answered with
+// e instead of invoking the handler. Multiple injected errors are consumed in
+// order, one per call.
+func (s *Server) InjectError(e *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injected = append(s.injected, e)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *wireError      `json:"error,omitempty"`
+}
+
+type wireError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var out interface{}
+	var batch []request
+	var single request
+	switch {
+	case json.Unmarshal(body, &batch) == nil:
+		responses := make([]response, len(batch))
+		for i, req := range batch {
+			responses[i] = s.answer(req)
+		}
+		out = responses
+	case json.Unmarshal(body, &single) == nil:
+		out = s.answer(single)
+	default:
+		out = response{
+			JSONRPC: "2.0",
+			ID:      json.RawMessage("null"),
+			Error:   &wireError{Code: CodeParseError, Message: "parse error"},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+func (s *Server) answer(req request) response {
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	if req.Method != "trp.resolve" {
+		resp.Error = &wireError{Code: CodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+		return resp
+	}
+	s.capture(req)
+	if injected := s.nextInjected(); injected != nil {
+		resp.Error = toWire(injected)
+		return resp
+	}
+
+	var params trp.ResolveParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		resp.Error = &wireError{Code: CodeParseError, Message: err.Error()}
+		return resp
+	}
+	envelope, err := s.handler(params)
+	if err != nil {
+		resp.Error = toWire(err)
+		return resp
+	}
+	resp.Result = envelope
+	return resp
+}
+
+func (s *Server) nextInjected() *Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.injected) == 0 {
+		return nil
+	}
+	e := s.injected[0]
+	s.injected = s.injected[1:]
+	return e
+}
+
+func toWire(err error) *wireError {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return &wireError{Code: rpcErr.Code, Message: rpcErr.Message, Data: rpcErr.Data}
+	}
+	return &wireError{Code: CodeServerError, Message: err.Error()}
+}
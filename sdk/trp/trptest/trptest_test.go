@@ -0,0 +1,178 @@
+package trptest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/core"
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+var tir = core.TirEnvelope{Content: "aabb", Encoding: "hex", Version: "v1beta0"}
+
+func TestServerResolves(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		if params.Tir.Content != "aabb" {
+			t.Errorf("expected TIR content 'aabb', got %q", params.Tir.Content)
+		}
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	envelope, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" || envelope.Tx != "beef" {
+		t.Errorf("unexpected envelope %+v", envelope)
+	}
+}
+
+func TestServerHandlerError(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return nil, &trptest.Error{Code: -32050, Message: "not enough funds", Data: map[string]interface{}{"missing": 5}}
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	_, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir})
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected GenericRpcError, got %T: %v", err, err)
+	}
+	if rpcErr.Code != -32050 || rpcErr.Message != "not enough funds" {
+		t.Errorf("unexpected error %+v", rpcErr)
+	}
+	if string(rpcErr.Data) != `{"missing":5}` {
+		t.Errorf("unexpected error data %s", rpcErr.Data)
+	}
+}
+
+func TestServerInjectedErrorIsOneShot(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+	srv.InjectError(&trptest.Error{Code: -32000, Message: "try again"})
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir}); err == nil {
+		t.Fatal("expected injected error")
+	}
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir}); err != nil {
+		t.Fatalf("expected second call to succeed, got %v", err)
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: params.Args["name"].(string), Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	envelopes, errs, err := client.ResolveBatch(context.Background(), []trp.ResolveParams{
+		{Tir: tir, Args: map[string]interface{}{"name": "a"}},
+		{Tir: tir, Args: map[string]interface{}{"name": "b"}},
+	})
+	if err != nil {
+		t.Fatalf("ResolveBatch failed: %v", err)
+	}
+	for i, want := range []string{"a", "b"} {
+		if errs[i] != nil || envelopes[i].Hash != want {
+			t.Errorf("item %d: expected hash %q, got %+v (err %v)", i, want, envelopes[i], errs[i])
+		}
+	}
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return nil, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	err := client.Ping(context.Background())
+	var rpcErr *trp.GenericRpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != trptest.CodeMethodNotFound {
+		t.Fatalf("expected method-not-found error, got %v", err)
+	}
+}
+
+func TestMethodServer(t *testing.T) {
+	srv := trptest.NewMethodServer(map[string]interface{}{
+		"trp.capabilities": map[string]interface{}{"methods": []string{"trp.resolve"}},
+		"trp.health":       &trptest.Error{Code: trptest.CodeServerError, Message: "unhealthy"},
+	})
+	defer srv.Close()
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: srv.URL})
+	caps, err := client.Capabilities(context.Background())
+	if err != nil || !caps.Supports("trp.resolve") {
+		t.Fatalf("expected capabilities from the results table, got %+v (err %v)", caps, err)
+	}
+	var rpcErr *trp.GenericRpcError
+	if err := client.Ping(context.Background()); !errors.As(err, &rpcErr) || rpcErr.Message != "unhealthy" {
+		t.Errorf("expected the canned error, got %v", err)
+	}
+	if _, err := client.CheckStatus(context.Background(), []string{"abc"}); !errors.As(err, &rpcErr) || rpcErr.Code != trptest.CodeMethodNotFound {
+		t.Errorf("expected method-not-found for a method without a result, got %v", err)
+	}
+}
+
+func TestServerCapturesRequests(t *testing.T) {
+	srv := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer srv.Close()
+
+	client := trp.NewClientWithOptions(srv.URL, trp.WithEnvArg("network", "preview"))
+	args := map[string]interface{}{"quantity": uint64(18446744073709551615), "sender": "addr1"}
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir, Args: args}); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 captured request, got %d", len(requests))
+	}
+	captured := requests[0]
+	captured.AssertArgs(t, args)
+	if captured.Tir() != tir {
+		t.Errorf("unexpected TIR %+v", captured.Tir())
+	}
+	if captured.Env()["network"] != "preview" {
+		t.Errorf("expected the merged env, got %v", captured.Env())
+	}
+}
+
+func TestReplayServer(t *testing.T) {
+	live := trptest.NewServer(func(params trp.ResolveParams) (*trp.TxEnvelope, error) {
+		return &trp.TxEnvelope{Hash: "abc", Tx: "beef"}, nil
+	})
+	defer live.Close()
+
+	var recorded []byte
+	client := trp.NewClientWithOptions(live.URL, trp.WithResponseInterceptor(func(_ int, body []byte) { recorded = body }))
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir}); err != nil {
+		t.Fatalf("recording Resolve failed: %v", err)
+	}
+
+	replay := trptest.NewReplayServer(recorded)
+	defer replay.Close()
+	client = trp.NewClient(trp.ClientOptions{Endpoint: replay.URL})
+	envelope, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir})
+	if err != nil {
+		t.Fatalf("replayed Resolve failed: %v", err)
+	}
+	if envelope.Hash != "abc" || envelope.Tx != "beef" {
+		t.Errorf("expected the recorded envelope, got %+v", envelope)
+	}
+	var rpcErr *trp.GenericRpcError
+	if _, err := client.Resolve(context.Background(), trp.ResolveParams{Tir: tir}); !errors.As(err, &rpcErr) || rpcErr.Code != trptest.CodeServerError {
+		t.Errorf("expected an error once the recording is exhausted, got %v", err)
+	}
+}
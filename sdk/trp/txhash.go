@@ -0,0 +1,70 @@
+package trp
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"golang.org/x/crypto/blake2b"
+)
+
+// resolved decodes a trp.resolve result and applies the checks enabled by
+// ValidateEnvelope and VerifyHash.
+func (c *Client) resolved(result []byte) (*TxEnvelope, error) {
+	envelope, err := decodeEnvelope(result, c.options.StrictDecoding)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Lenient = c.options.LenientEncoding
+	if c.options.ValidateEnvelope {
+		if err := validateEnvelope(envelope); err != nil {
+			return nil, err
+		}
+	}
+	if c.options.VerifyHash {
+		if err := verifyTxHash(envelope); err != nil {
+			return nil, err
+		}
+	}
+	return envelope, nil
+}
+
+// verifyTxHash recomputes a Cardano transaction id, the Blake2b-256 digest
+// of the CBOR-encoded body (the first element of the transaction array),
+// and compares it with envelope.Hash.
+func verifyTxHash(envelope *TxEnvelope) error {
+	tx, err := envelope.Bytes()
+	if err != nil {
+		return &DeserializationError{Cause: err, Raw: envelope.Tx}
+	}
+	body, err := cborTxBody(tx)
+	if err != nil {
+		return &DeserializationError{Cause: fmt.Errorf("tx is not a valid CBOR transaction: %w", err), Raw: envelope.Tx}
+	}
+	sum := blake2b.Sum256(body)
+	computed := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(computed, envelope.Hash) {
+		return &TxHashMismatchError{Advertised: envelope.Hash, Computed: computed}
+	}
+	return nil
+}
+
+// txDecMode decodes the outer transaction array. The nesting cap bounds
+// how deep hostile input can drive the decoder; real transactions, Plutus
+// data included, stay well below it.
+var txDecMode, _ = cbor.DecOptions{MaxNestedLevels: 256}.DecMode()
+
+// cborTxBody returns the raw bytes of the first element of the CBOR array in
+// tx.
+func cborTxBody(tx []byte) ([]byte, error) {
+	var items []cbor.RawMessage
+	if err := txDecMode.Unmarshal(tx, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("transaction array is empty")
+	}
+	return items[0], nil
+}
@@ -0,0 +1,91 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+// [ {0: [], 2: 170000}, {}, true, null ] with an indefinite-length witness map.
+const (
+	testTxBody = "a20080021a00029810"
+	testTx     = "84" + testTxBody + "bfff" + "f5f6"
+)
+
+func testTxHash() string {
+	body, _ := hex.DecodeString(testTxBody)
+	sum := blake2b.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func envelopeServer(t *testing.T, hash, tx string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := trptest.RequestID(r)
+		trptest.WriteResult(w, id, map[string]interface{}{"hash": hash, "tx": tx})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyHashAcceptsMatchingHash(t *testing.T) {
+	server := envelopeServer(t, testTxHash(), testTx)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, VerifyHash: true})
+	envelope, err := client.Resolve(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if envelope.Hash != testTxHash() {
+		t.Errorf("unexpected hash %q", envelope.Hash)
+	}
+}
+
+func TestVerifyHashRejectsMismatch(t *testing.T) {
+	wrong := "00" + testTxHash()[2:]
+	server := envelopeServer(t, wrong, testTx)
+
+	client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, VerifyHash: true})
+	_, err := client.Resolve(context.Background(), testParams())
+	var mismatch *trp.TxHashMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected TxHashMismatchError, got %T: %v", err, err)
+	}
+	if mismatch.Advertised != wrong || mismatch.Computed != testTxHash() {
+		t.Errorf("unexpected mismatch %+v", mismatch)
+	}
+
+	// Without VerifyHash the envelope is returned untouched.
+	client = trp.NewClient(trp.ClientOptions{Endpoint: server.URL})
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+}
+
+func TestVerifyHashRejectsMalformedTx(t *testing.T) {
+	for name, tx := range map[string]string{
+		"truncated":  "84a200",
+		"not array":  "a0",
+		"empty":      "80",
+		"deep":       strings.Repeat("81", 100000) + "00",
+		"huge count": "9bffffffffffffffff",
+	} {
+		server := envelopeServer(t, testTxHash(), tx)
+
+		client := trp.NewClient(trp.ClientOptions{Endpoint: server.URL, VerifyHash: true})
+		_, err := client.Resolve(context.Background(), testParams())
+		var deserErr *trp.DeserializationError
+		if !errors.As(err, &deserErr) {
+			t.Errorf("%s: expected DeserializationError, got %T: %v", name, err, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package trp
+
+// Version is the release of this SDK, reported in the default User-Agent.
+const Version = "0.1.0"
+
+// defaultUserAgent identifies the SDK to TRP servers unless overridden by
+// ClientOptions.UserAgent or a User-Agent entry in Headers.
+const defaultUserAgent = "tx3-go-sdk/" + Version
+
+// userAgent returns the effective User-Agent header value.
+func (c *Client) userAgent() string {
+	if c.options.UserAgent != "" {
+		return c.options.UserAgent
+	}
+	return defaultUserAgent
+}
@@ -0,0 +1,50 @@
+package trp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Warmup opens a connection to every configured endpoint, with a trp.health
+// request, so the first real calls do not pay for DNS, TCP and TLS setup.
+// Endpoints are warmed concurrently, each with the client's headers and
+// credentials, timeout and circuit breaker but without retries.
+//
+// Any HTTP response counts as warm, even an error status or a server without
+// trp.health: the connection is in the pool either way. Warmup returns the
+// failures of the endpoints it could not reach, joined, or nil.
+func (c *Client) Warmup(ctx context.Context) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	ctx, leave, err := c.enter(ctx)
+	if err != nil {
+		return err
+	}
+	defer leave()
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	endpoints := c.endpoints()
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := c.newRequest("trp.health", struct{}{})
+			body, err := c.codec().Marshal(req)
+			if err == nil {
+				_, err = c.post(ctx, endpoint, outgoing{method: req.Method, id: req.ID, body: body})
+			}
+			var httpErr *HttpError
+			if err != nil && !errors.As(err, &httpErr) {
+				errs[i] = fmt.Errorf("warm up %s: %w", endpoint, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package trp_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+	"github.com/tx3-lang/go-sdk/sdk/trp/trptest"
+)
+
+func TestWarmupPrimesEveryEndpoint(t *testing.T) {
+	var conns [2]atomic.Int32
+	servers := make([]*httptest.Server, 2)
+	for i := range servers {
+		servers[i] = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if i == 1 {
+				// Servers without trp.health still leave a pooled connection.
+				trptest.WriteError(w, trptest.RequestID(r), &trptest.Error{Code: trptest.CodeMethodNotFound, Message: "method not found"})
+				return
+			}
+			trptest.WriteResult(w, trptest.RequestID(r), map[string]interface{}{"hash": "abc", "tx": "beef"})
+		}))
+		servers[i].Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns[i].Add(1)
+			}
+		}
+		servers[i].Start()
+		defer servers[i].Close()
+	}
+
+	client := trp.NewClient(trp.ClientOptions{Endpoints: []string{servers[0].URL, servers[1].URL}})
+	defer client.Close()
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if conns[0].Load() != 1 || conns[1].Load() != 1 {
+		t.Fatalf("expected one connection per endpoint, got %d and %d", conns[0].Load(), conns[1].Load())
+	}
+	if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got := conns[0].Load(); got != 1 {
+		t.Errorf("expected Resolve to reuse the warm connection, got %d connections", got)
+	}
+
+	unreachable := trp.NewClient(trp.ClientOptions{Endpoints: []string{servers[0].URL, "http://127.0.0.1:1"}})
+	if err := unreachable.Warmup(context.Background()); err == nil || !strings.Contains(err.Error(), "127.0.0.1:1") {
+		t.Errorf("expected an error naming the unreachable endpoint, got %v", err)
+	}
+}
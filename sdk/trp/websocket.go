@@ -0,0 +1,327 @@
+package trp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/coder/websocket"
+)
+
+// WebSocketClient speaks TRP JSON-RPC over a single long-lived WebSocket
+// connection, multiplexing concurrent calls and correlating responses by id.
+//
+// The connection is dialled lazily on the first call. If it drops, calls in
+// flight fail with *ConnectionClosedError and the next call dials again;
+// with Retry configured, the failed calls are retried on the new connection.
+//
+// WebSocketClient honours the same ClientOptions as Client, except that
+// Endpoints, Codec, Compression, StreamRequests, RateLimiter,
+// MaxConcurrentRequests, ChunkedResults, ResponseInterceptor,
+// RequestRecorder, Signer and CircuitBreaker do not apply (messages are
+// always JSON text frames), ForceHTTP2 does not either (the handshake is an
+// HTTP/1.1 upgrade), and Headers and credentials are sent once, on the
+// handshake. Per-call headers are ignored for the same reason. It is safe
+// for concurrent use.
+type WebSocketClient struct {
+	url  string
+	base *Client // shared request building, env merging, retries and hooks
+
+	mu   sync.Mutex // guards conn and serialises dialling
+	conn *wsConn
+}
+
+// wsConn is one WebSocket connection and the calls waiting on it.
+type wsConn struct {
+	ws   *websocket.Conn
+	done chan struct{} // closed when the connection drops
+	err  error         // why the connection dropped; set before done closes
+
+	mu      sync.Mutex
+	pending map[string]chan jsonRPCResponse
+	once    sync.Once
+}
+
+// NewWebSocketClient creates a TRP client for the ws:// or wss:// url.
+// options.Endpoint, options.Endpoints and options.EndpointConfigs are
+// ignored.
+func NewWebSocketClient(url string, options ClientOptions) *WebSocketClient {
+	options.Endpoint = url
+	options.Endpoints = nil
+	options.EndpointConfigs = nil
+	options.ForceHTTP2 = false
+	return &WebSocketClient{url: url, base: newClient(options)}
+}
+
+// Resolve invokes the trp.resolve JSON-RPC method over the WebSocket. See
+// Client.Resolve.
+func (w *WebSocketClient) Resolve(ctx context.Context, params ResolveParams, opts ...CallOption) (envelope *TxEnvelope, err error) {
+	defer w.base.observeResolve(w.base.clock().Now(), &err)
+	env, err := w.base.defaultEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params, err = w.base.prepareResolve(params, env)
+	if err != nil {
+		return nil, err
+	}
+	params = newCallOptions(opts).withLabels(params)
+	result, err := w.call(ctx, w.base.resolveMethod(), params, opts)
+	if fallback, ok := w.base.fallbackMethod(err); ok {
+		result, err = w.call(ctx, fallback, params, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w.base.resolved(result)
+}
+
+// Submit invokes the trp.submit JSON-RPC method over the WebSocket. See
+// Client.Submit.
+func (w *WebSocketClient) Submit(ctx context.Context, params SubmitParams, opts ...CallOption) (*SubmitResponse, error) {
+	result, err := w.call(ctx, "trp.submit", params, opts)
+	if err != nil {
+		return nil, err
+	}
+	var resp SubmitResponse
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, &DeserializationError{Cause: err, Raw: string(result)}
+	}
+	return &resp, nil
+}
+
+// CheckStatus invokes the trp.checkStatus JSON-RPC method over the
+// WebSocket.
+func (w *WebSocketClient) CheckStatus(ctx context.Context, hashes []string, opts ...CallOption) (*CheckStatusResponse, error) {
+	result, err := w.call(ctx, "trp.checkStatus", CheckStatusParams{Hashes: hashes}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var resp CheckStatusResponse
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, &DeserializationError{Cause: err, Raw: string(result)}
+	}
+	return &resp, nil
+}
+
+// Close closes the connection and marks the client closed. Calls in flight
+// fail with *ConnectionClosedError; later calls fail with
+// *ClientClosedError.
+func (w *WebSocketClient) Close() error {
+	w.base.Close()
+	w.mu.Lock()
+	conn := w.conn
+	w.conn = nil
+	w.mu.Unlock()
+	if conn != nil {
+		conn.drop(errors.New("client closed"))
+		return conn.ws.Close(websocket.StatusNormalClosure, "")
+	}
+	return nil
+}
+
+// call executes a JSON-RPC method over the socket through the client's
+// middleware, retrying per the client's RetryOptions. The request id stays
+// the same across attempts.
+func (w *WebSocketClient) call(ctx context.Context, method string, params interface{}, opts []CallOption) (json.RawMessage, error) {
+	if err := w.base.checkOpen(); err != nil {
+		return nil, err
+	}
+	h := w.base.withMiddleware(func(ctx context.Context, call *Call) (json.RawMessage, error) {
+		return w.invoke(ctx, call.Method, call.Params, opts)
+	})
+	return h(ctx, &Call{Method: method, Params: params})
+}
+
+// invoke sends a single call past the middleware.
+func (w *WebSocketClient) invoke(ctx context.Context, method string, params interface{}, opts []CallOption) (json.RawMessage, error) {
+	req := w.base.newRequest(method, params)
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, &NetworkError{Cause: fmt.Errorf("failed to marshal request: %w", err)}
+	}
+	out := outgoing{method: method, id: req.ID, body: bodyBytes, idempotent: true, call: newCallOptions(opts)}
+
+	ctx, leave, err := w.base.enter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	ctx, cancel := w.base.withRequestTimeout(ctx)
+	defer cancel()
+	ctx, finish := w.base.startCall(ctx, out)
+	var result json.RawMessage
+	err = w.base.retry(ctx, true, func() error {
+		resp, err := w.roundTrip(ctx, out)
+		if err != nil {
+			return err
+		}
+		if err := w.base.checkResponse(resp, out.id); err != nil {
+			return err
+		}
+		result, err = resp.result(w.base.options.ErrorFormatter)
+		return err
+	})
+	finish(result, err)
+	return result, err
+}
+
+// roundTrip sends out on the current connection, dialling if needed, and
+// waits for the response carrying the same id.
+func (w *WebSocketClient) roundTrip(ctx context.Context, out outgoing) (*jsonRPCResponse, error) {
+	timeout := w.base.timeout
+	if out.call.timeout > 0 {
+		timeout = out.call.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.register(out.id)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.unregister(out.id)
+
+	start := w.base.clock().Now()
+	if err := conn.ws.Write(ctx, websocket.MessageText, out.body); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &NetworkError{Cause: fmt.Errorf("%s aborted: %w", out.method, ctxErr)}
+		}
+		conn.drop(err)
+		return nil, &ConnectionClosedError{Cause: err}
+	}
+
+	select {
+	case resp := <-ch:
+		w.base.debugf("trp: %s id=%s endpoint=%s elapsed=%s", out.method, out.id, w.url, w.base.since(start))
+		return &resp, nil
+	case <-conn.done:
+		return nil, &ConnectionClosedError{Cause: conn.err}
+	case <-ctx.Done():
+		return nil, &NetworkError{Cause: fmt.Errorf("%s aborted: %w", out.method, ctx.Err())}
+	}
+}
+
+// connect returns the live connection, dialling a new one if there is none.
+func (w *WebSocketClient) connect(ctx context.Context) (*wsConn, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		select {
+		case <-w.conn.done:
+			w.conn = nil
+		default:
+			return w.conn, nil
+		}
+	}
+
+	header := http.Header{}
+	header.Set("User-Agent", w.base.userAgent())
+	for k, v := range w.base.options.Headers {
+		header.Set(k, v)
+	}
+	if err := w.base.applyAuth(ctx, header); err != nil {
+		return nil, err
+	}
+	ws, _, err := websocket.Dial(ctx, w.url, &websocket.DialOptions{
+		HTTPClient: w.base.httpClient,
+		HTTPHeader: header,
+	})
+	if err != nil {
+		w.base.debugf("trp: dial endpoint=%s failed: %v", w.url, err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &NetworkError{Cause: fmt.Errorf("dial aborted: %w", ctxErr)}
+		}
+		return nil, &NetworkError{Cause: err}
+	}
+	limit := w.base.maxResponseBytes()
+	if limit <= 0 {
+		limit = -1 // no cap
+	}
+	ws.SetReadLimit(limit)
+
+	conn := &wsConn{ws: ws, done: make(chan struct{}), pending: make(map[string]chan jsonRPCResponse)}
+	go w.readLoop(conn)
+	w.conn = conn
+	return conn, nil
+}
+
+// readLoop dispatches incoming responses to their waiting calls, and
+// notifications to the NotificationHandler, until the connection fails. An
+// error with a null id answers a request the server could not parse; as
+// there is no telling which, every waiting call fails with it.
+func (w *WebSocketClient) readLoop(conn *wsConn) {
+	for {
+		_, data, err := conn.ws.Read(context.Background())
+		if err != nil {
+			conn.drop(err)
+			conn.ws.CloseNow()
+			return
+		}
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			w.base.debugf("trp: endpoint=%s discarding undecodable message: %v", w.url, err)
+			continue
+		}
+		if resp.isNotification() {
+			w.base.notify(&resp)
+			continue
+		}
+		conn.mu.Lock()
+		if resp.ID == "" {
+			for id, ch := range conn.pending {
+				ch <- resp
+				delete(conn.pending, id)
+			}
+			conn.mu.Unlock()
+			continue
+		}
+		ch := conn.pending[resp.ID]
+		delete(conn.pending, resp.ID)
+		conn.mu.Unlock()
+		if ch != nil {
+			ch <- resp
+		} else {
+			w.base.debugf("trp: endpoint=%s discarding response to unknown id %s", w.url, resp.ID)
+		}
+	}
+}
+
+func (c *wsConn) register(id string) (chan jsonRPCResponse, error) {
+	ch := make(chan jsonRPCResponse, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return nil, &ConnectionClosedError{Cause: c.err}
+	default:
+	}
+	c.pending[id] = ch
+	return ch, nil
+}
+
+func (c *wsConn) unregister(id string) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// drop marks the connection failed with err, waking every waiting call.
+func (c *wsConn) drop(err error) {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		close(c.done)
+		c.mu.Unlock()
+	})
+}
@@ -0,0 +1,189 @@
+package trp_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/tx3-lang/go-sdk/sdk/trp"
+)
+
+type wsRequest struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+}
+
+// wsServer accepts WebSocket connections and hands each one to serve. It
+// returns the ws:// URL and a counter of accepted connections.
+func wsServer(t *testing.T, serve func(ctx context.Context, conn *websocket.Conn)) (string, *int32) {
+	t.Helper()
+	var conns int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		atomic.AddInt32(&conns, 1)
+		serve(r.Context(), conn)
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http"), &conns
+}
+
+func readWSRequest(ctx context.Context, conn *websocket.Conn) (wsRequest, error) {
+	var req wsRequest
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return req, err
+	}
+	return req, json.Unmarshal(data, &req)
+}
+
+func writeWSResult(ctx context.Context, conn *websocket.Conn, id, hash string) error {
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"result":  map[string]interface{}{"hash": hash, "tx": "beef"},
+	})
+	return conn.Write(ctx, websocket.MessageText, data)
+}
+
+func TestWebSocketCorrelatesOutOfOrderResponses(t *testing.T) {
+	url, conns := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		// Collect two requests, then answer them in reverse order, echoing
+		// each request's env tag as the hash.
+		var batch []wsRequest
+		var raw [][]byte
+		for len(batch) < 2 {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var req wsRequest
+			json.Unmarshal(data, &req)
+			batch = append(batch, req)
+			raw = append(raw, data)
+		}
+		for i := len(batch) - 1; i >= 0; i-- {
+			var body struct {
+				Params struct {
+					Env map[string]string `json:"env"`
+				} `json:"params"`
+			}
+			json.Unmarshal(raw[i], &body)
+			writeWSResult(ctx, conn, batch[i].ID, body.Params.Env["tag"])
+		}
+		conn.Read(ctx)
+	})
+
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{})
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for _, tag := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(tag string) {
+			defer wg.Done()
+			params := testParams()
+			params.Env = map[string]interface{}{"tag": tag}
+			envelope, err := client.Resolve(context.Background(), params)
+			if err != nil {
+				t.Errorf("Resolve(%s) failed: %v", tag, err)
+				return
+			}
+			if envelope.Hash != tag {
+				t.Errorf("Resolve(%s) got the response for %q", tag, envelope.Hash)
+			}
+		}(tag)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(conns); got != 1 {
+		t.Errorf("expected calls to share one connection, got %d", got)
+	}
+}
+
+func TestWebSocketReconnectsAfterDrop(t *testing.T) {
+	url, conns := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		// Answer a single request per connection, then hang up.
+		req, err := readWSRequest(ctx, conn)
+		if err != nil {
+			return
+		}
+		writeWSResult(ctx, conn, req.ID, "abc")
+		conn.Close(websocket.StatusGoingAway, "bye")
+	})
+
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{Retry: fastRetry(3)})
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Resolve(context.Background(), testParams()); err != nil {
+			t.Fatalf("Resolve %d failed: %v", i, err)
+		}
+	}
+	if got := atomic.LoadInt32(conns); got < 2 {
+		t.Errorf("expected a reconnection, got %d connection(s)", got)
+	}
+}
+
+func TestWebSocketConnectionClosedError(t *testing.T) {
+	url, _ := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		readWSRequest(ctx, conn)
+		conn.Close(websocket.StatusInternalError, "crashed")
+	})
+
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{})
+	defer client.Close()
+
+	_, err := client.Resolve(context.Background(), testParams())
+	var closedErr *trp.ConnectionClosedError
+	if !errors.As(err, &closedErr) {
+		t.Fatalf("expected ConnectionClosedError, got %T: %v", err, err)
+	}
+}
+
+func TestWebSocketNullIDError(t *testing.T) {
+	url, _ := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		if _, err := readWSRequest(ctx, conn); err != nil {
+			return
+		}
+		conn.Write(ctx, websocket.MessageText, []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`))
+		conn.Read(ctx)
+	})
+
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Resolve(ctx, testParams())
+	if code, ok := trp.RpcErrorCode(err); !ok || code != trp.ErrCodeParseError {
+		t.Fatalf("expected the parse error to fail the call, got %T: %v", err, err)
+	}
+}
+
+func TestWebSocketClosedClient(t *testing.T) {
+	url, _ := wsServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		conn.Read(ctx)
+	})
+
+	client := trp.NewWebSocketClient(url, trp.ClientOptions{})
+	client.Close()
+
+	_, err := client.Resolve(context.Background(), testParams())
+	var closedErr *trp.ClientClosedError
+	if !errors.As(err, &closedErr) {
+		t.Fatalf("expected ClientClosedError, got %T: %v", err, err)
+	}
+}
@@ -49,7 +49,12 @@ func ProtocolFromBytes(data []byte) (*tii.Protocol, error) {
 // TRPClientOptions configures a TRP client.
 type TRPClientOptions = trp.ClientOptions
 
-// NewTRPClient creates a new low-level TRP JSON-RPC client.
+// TRPResolver is the interface satisfied by TRP clients that can resolve
+// transactions; depend on it to substitute fakes in tests.
+type TRPResolver = trp.Resolver
+
+// NewTRPClient creates a new low-level TRP JSON-RPC client. It returns the
+// concrete *trp.Client, which satisfies TRPResolver.
 func NewTRPClient(options TRPClientOptions) *trp.Client {
 	return trp.NewClient(options)
 }